@@ -0,0 +1,457 @@
+package gmath
+
+import (
+	"math"
+)
+
+// AABBTree is a dynamic bounding volume hierarchy over axis-aligned rectangles.
+// It's a good broad-phase structure for the objects of very different sizes.
+//
+// Every leaf stores a fattened rectangle: the item bounds extended by the tree margin.
+// Small movements that stay inside the fat bounds don't require the tree updates.
+// The tree is kept balanced with rotations.
+//
+// The items are identified by the ids returned from [AABBTree.Insert].
+// An id remains valid until the item is removed, the updates do not change it.
+//
+// The queries reuse an internal buffer, so the tree is not safe for the concurrent use.
+type AABBTree[T any] struct {
+	margin float64
+
+	nodes    []aabbTreeNode[T]
+	root     int32
+	freeList int32
+	numItems int
+
+	stack []int32
+
+	// rayStack is used by RayCast: its callback can run other queries
+	// that would reuse the shared stack otherwise.
+	rayStack []int32
+}
+
+type aabbTreeNode[T any] struct {
+	// bounds are the fat bounds for the leaf nodes;
+	// for the internal nodes, it's a union of the children bounds.
+	bounds Rect
+
+	value T
+
+	// parent is a next free node index for the free nodes.
+	parent int32
+	child1 int32 // -1 for the leaf nodes
+	child2 int32
+
+	// height is 0 for the leaves and -1 for the free nodes.
+	height int32
+}
+
+// NewAABBTree creates an empty tree with the specified fat bounds margin.
+func NewAABBTree[T any](margin float64) *AABBTree[T] {
+	return &AABBTree[T]{
+		margin:   margin,
+		root:     -1,
+		freeList: -1,
+	}
+}
+
+// Len returns the number of items inside the tree.
+func (t *AABBTree[T]) Len() int { return t.numItems }
+
+// Height returns the tree height; it's 0 for a tree with a single item.
+// For an empty tree, -1 is returned.
+func (t *AABBTree[T]) Height() int {
+	if t.root == -1 {
+		return -1
+	}
+	return int(t.nodes[t.root].height)
+}
+
+// Insert adds an item with the given bounds to the tree.
+// It returns the item id.
+func (t *AABBTree[T]) Insert(bounds Rect, value T) int {
+	id := t.allocNode()
+	node := &t.nodes[id]
+	node.bounds = t.fatten(bounds)
+	node.value = value
+	node.height = 0
+	t.insertLeaf(id)
+	t.numItems++
+	return int(id)
+}
+
+// Remove deletes the item from the tree.
+// The id must be valid.
+func (t *AABBTree[T]) Remove(id int) {
+	t.removeLeaf(int32(id))
+	t.freeNode(int32(id))
+	t.numItems--
+}
+
+// Update changes the item bounds.
+//
+// If the new bounds are still inside the fat bounds, the tree is not changed.
+// It returns true if the item was re-inserted.
+func (t *AABBTree[T]) Update(id int, bounds Rect) bool {
+	if rectEncloses(t.nodes[id].bounds, bounds) {
+		return false
+	}
+	t.removeLeaf(int32(id))
+	t.nodes[id].bounds = t.fatten(bounds)
+	t.insertLeaf(int32(id))
+	return true
+}
+
+// Value returns the item associated value.
+func (t *AABBTree[T]) Value(id int) T {
+	return t.nodes[id].value
+}
+
+// FatBounds returns the item fat bounds stored in the tree.
+func (t *AABBTree[T]) FatBounds(id int) Rect {
+	return t.nodes[id].bounds
+}
+
+// QueryRect appends the ids of the items which fat bounds overlap the area to dst.
+// The touching items are included.
+func (t *AABBTree[T]) QueryRect(dst []int, area Rect) []int {
+	if t.root == -1 {
+		return dst
+	}
+	stack := append(t.stack[:0], t.root)
+	for len(stack) != 0 {
+		nodeIndex := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &t.nodes[nodeIndex]
+		if !rectsTouch(node.bounds, area) {
+			continue
+		}
+		if node.child1 == -1 {
+			dst = append(dst, int(nodeIndex))
+		} else {
+			stack = append(stack, node.child1, node.child2)
+		}
+	}
+	t.stack = stack
+	return dst
+}
+
+// Pairs appends the pairs of items which fat bounds overlap to dst.
+// The touching items are included.
+//
+// Every pair is reported only once, the smaller id goes first.
+func (t *AABBTree[T]) Pairs(dst [][2]int) [][2]int {
+	for id := range t.nodes {
+		leaf := &t.nodes[id]
+		if leaf.height != 0 {
+			continue
+		}
+		stack := append(t.stack[:0], t.root)
+		for len(stack) != 0 {
+			nodeIndex := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			node := &t.nodes[nodeIndex]
+			if !rectsTouch(node.bounds, leaf.bounds) {
+				continue
+			}
+			if node.child1 != -1 {
+				stack = append(stack, node.child1, node.child2)
+				continue
+			}
+			if int(nodeIndex) > id {
+				dst = append(dst, [2]int{id, int(nodeIndex)})
+			}
+		}
+		t.stack = stack
+	}
+	return dst
+}
+
+// RayCast calls the hit function for every item which fat bounds
+// are hit by the ray within maxDist from its origin.
+// The dir is expected to be normalized.
+//
+// The hit function returns a new max distance.
+// It should return the maxDist argument to continue the search unchanged,
+// a smaller value to clip the ray (e.g. after a precise hit test)
+// or a negative value to stop the search.
+//
+// The hit function can run other queries on the tree (including RayCast),
+// but it must not insert, update or remove the items.
+func (t *AABBTree[T]) RayCast(origin, dir Vec, maxDist float64, hit func(id int, maxDist float64) float64) {
+	if t.root == -1 {
+		return
+	}
+	// The stack is detached while the search is running,
+	// so a nested RayCast call would allocate its own one.
+	stack := append(t.rayStack[:0], t.root)
+	t.rayStack = nil
+	for len(stack) != 0 {
+		nodeIndex := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &t.nodes[nodeIndex]
+		dist, ok := rayRect(origin, dir, node.bounds)
+		if !ok || dist > maxDist {
+			continue
+		}
+		if node.child1 != -1 {
+			stack = append(stack, node.child1, node.child2)
+			continue
+		}
+		maxDist = hit(int(nodeIndex), maxDist)
+		if maxDist < 0 {
+			break
+		}
+	}
+	t.rayStack = stack[:0]
+}
+
+func (t *AABBTree[T]) fatten(r Rect) Rect {
+	m := Vec{X: t.margin, Y: t.margin}
+	return Rect{Min: r.Min.Sub(m), Max: r.Max.Add(m)}
+}
+
+func (t *AABBTree[T]) allocNode() int32 {
+	if t.freeList == -1 {
+		t.nodes = append(t.nodes, aabbTreeNode[T]{})
+		t.freeList = int32(len(t.nodes) - 1)
+		t.nodes[t.freeList].parent = -1
+	}
+	id := t.freeList
+	node := &t.nodes[id]
+	t.freeList = node.parent
+	*node = aabbTreeNode[T]{parent: -1, child1: -1, child2: -1}
+	return id
+}
+
+func (t *AABBTree[T]) freeNode(id int32) {
+	t.nodes[id] = aabbTreeNode[T]{
+		parent: t.freeList,
+		child1: -1,
+		child2: -1,
+		height: -1,
+	}
+	t.freeList = id
+}
+
+func (t *AABBTree[T]) insertLeaf(leaf int32) {
+	if t.root == -1 {
+		t.root = leaf
+		t.nodes[leaf].parent = -1
+		return
+	}
+
+	// Find the best sibling using the surface area heuristic
+	// (the perimeter is used as a 2D analogue).
+	leafBounds := t.nodes[leaf].bounds
+	index := t.root
+	for t.nodes[index].child1 != -1 {
+		node := &t.nodes[index]
+		area := rectPerimeter(node.bounds)
+		combinedArea := rectPerimeter(rectUnion(node.bounds, leafBounds))
+
+		// The cost of creating a new parent for this node and the leaf.
+		cost := 2 * combinedArea
+		// The minimum cost of pushing the leaf further down the tree.
+		inheritanceCost := 2 * (combinedArea - area)
+
+		cost1 := t.descendCost(node.child1, leafBounds) + inheritanceCost
+		cost2 := t.descendCost(node.child2, leafBounds) + inheritanceCost
+		if cost < cost1 && cost < cost2 {
+			break
+		}
+		if cost1 < cost2 {
+			index = node.child1
+		} else {
+			index = node.child2
+		}
+	}
+	sibling := index
+
+	oldParent := t.nodes[sibling].parent
+	newParent := t.allocNode()
+	t.nodes[newParent] = aabbTreeNode[T]{
+		bounds: rectUnion(leafBounds, t.nodes[sibling].bounds),
+		parent: oldParent,
+		child1: sibling,
+		child2: leaf,
+		height: t.nodes[sibling].height + 1,
+	}
+	if oldParent != -1 {
+		if t.nodes[oldParent].child1 == sibling {
+			t.nodes[oldParent].child1 = newParent
+		} else {
+			t.nodes[oldParent].child2 = newParent
+		}
+	} else {
+		t.root = newParent
+	}
+	t.nodes[sibling].parent = newParent
+	t.nodes[leaf].parent = newParent
+
+	t.refit(t.nodes[leaf].parent)
+}
+
+func (t *AABBTree[T]) descendCost(child int32, leafBounds Rect) float64 {
+	node := &t.nodes[child]
+	cost := rectPerimeter(rectUnion(leafBounds, node.bounds))
+	if node.child1 != -1 {
+		cost -= rectPerimeter(node.bounds)
+	}
+	return cost
+}
+
+func (t *AABBTree[T]) removeLeaf(leaf int32) {
+	if leaf == t.root {
+		t.root = -1
+		return
+	}
+
+	parent := t.nodes[leaf].parent
+	grandParent := t.nodes[parent].parent
+	sibling := t.nodes[parent].child1
+	if sibling == leaf {
+		sibling = t.nodes[parent].child2
+	}
+
+	if grandParent != -1 {
+		if t.nodes[grandParent].child1 == parent {
+			t.nodes[grandParent].child1 = sibling
+		} else {
+			t.nodes[grandParent].child2 = sibling
+		}
+		t.nodes[sibling].parent = grandParent
+		t.freeNode(parent)
+		t.refit(grandParent)
+	} else {
+		t.root = sibling
+		t.nodes[sibling].parent = -1
+		t.freeNode(parent)
+	}
+}
+
+// refit walks from the node up to the root,
+// balancing the nodes and fixing their bounds and heights.
+func (t *AABBTree[T]) refit(index int32) {
+	for index != -1 {
+		index = t.balance(index)
+		node := &t.nodes[index]
+		child1 := &t.nodes[node.child1]
+		child2 := &t.nodes[node.child2]
+		node.height = 1 + max32(child1.height, child2.height)
+		node.bounds = rectUnion(child1.bounds, child2.bounds)
+		index = node.parent
+	}
+}
+
+// balance performs a left or right rotation if the node a is imbalanced.
+// It returns the new root index of the subtree.
+func (t *AABBTree[T]) balance(iA int32) int32 {
+	a := &t.nodes[iA]
+	if a.child1 == -1 || a.height < 2 {
+		return iA
+	}
+
+	iB := a.child1
+	iC := a.child2
+	b := &t.nodes[iB]
+	c := &t.nodes[iC]
+	balance := c.height - b.height
+
+	switch {
+	case balance > 1:
+		// Rotate c up.
+		iF := c.child1
+		iG := c.child2
+		f := &t.nodes[iF]
+		g := &t.nodes[iG]
+
+		c.child1 = iA
+		c.parent = a.parent
+		a.parent = iC
+		t.replaceChild(c.parent, iA, iC)
+
+		if f.height > g.height {
+			c.child2 = iF
+			a.child2 = iG
+			g.parent = iA
+			a.bounds = rectUnion(b.bounds, g.bounds)
+			c.bounds = rectUnion(a.bounds, f.bounds)
+			a.height = 1 + max32(b.height, g.height)
+			c.height = 1 + max32(a.height, f.height)
+		} else {
+			c.child2 = iG
+			a.child2 = iF
+			f.parent = iA
+			a.bounds = rectUnion(b.bounds, f.bounds)
+			c.bounds = rectUnion(a.bounds, g.bounds)
+			a.height = 1 + max32(b.height, f.height)
+			c.height = 1 + max32(a.height, g.height)
+		}
+		return iC
+
+	case balance < -1:
+		// Rotate b up.
+		iD := b.child1
+		iE := b.child2
+		d := &t.nodes[iD]
+		e := &t.nodes[iE]
+
+		b.child1 = iA
+		b.parent = a.parent
+		a.parent = iB
+		t.replaceChild(b.parent, iA, iB)
+
+		if d.height > e.height {
+			b.child2 = iD
+			a.child1 = iE
+			e.parent = iA
+			a.bounds = rectUnion(c.bounds, e.bounds)
+			b.bounds = rectUnion(a.bounds, d.bounds)
+			a.height = 1 + max32(c.height, e.height)
+			b.height = 1 + max32(a.height, d.height)
+		} else {
+			b.child2 = iE
+			a.child1 = iD
+			d.parent = iA
+			a.bounds = rectUnion(c.bounds, d.bounds)
+			b.bounds = rectUnion(a.bounds, e.bounds)
+			a.height = 1 + max32(c.height, d.height)
+			b.height = 1 + max32(a.height, e.height)
+		}
+		return iB
+
+	default:
+		return iA
+	}
+}
+
+func (t *AABBTree[T]) replaceChild(parent, oldChild, newChild int32) {
+	if parent == -1 {
+		t.root = newChild
+		return
+	}
+	if t.nodes[parent].child1 == oldChild {
+		t.nodes[parent].child1 = newChild
+	} else {
+		t.nodes[parent].child2 = newChild
+	}
+}
+
+func rectUnion(a, b Rect) Rect {
+	return Rect{
+		Min: Vec{X: math.Min(a.Min.X, b.Min.X), Y: math.Min(a.Min.Y, b.Min.Y)},
+		Max: Vec{X: math.Max(a.Max.X, b.Max.X), Y: math.Max(a.Max.Y, b.Max.Y)},
+	}
+}
+
+func rectPerimeter(r Rect) float64 {
+	return 2 * (r.Width() + r.Height())
+}
+
+func max32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
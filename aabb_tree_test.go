@@ -0,0 +1,248 @@
+package gmath
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestAABBTreeRandomized(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := NewAABBTree[int](2)
+
+	items := make(map[int]Rect) // id => precise bounds
+	values := make(map[int]int)
+	randomRect := func() Rect {
+		pos := Vec{rng.Float64() * 500, rng.Float64() * 500}
+		// A mix of very small and very big objects.
+		size := Vec{rng.Float64() * 10, rng.Float64() * 10}
+		if rng.Intn(10) == 0 {
+			size = size.Mulf(20)
+		}
+		return Rect{Min: pos, Max: pos.Add(size)}
+	}
+
+	var ids []int
+	var pairs [][2]int
+	for round := 0; round < 40; round++ {
+		for j := 0; j < 25; j++ {
+			switch {
+			case len(items) != 0 && rng.Intn(4) == 0:
+				id := pickAABBTreeItem(rng, items)
+				tree.Remove(id)
+				delete(items, id)
+			case len(items) != 0 && rng.Intn(2) == 0:
+				id := pickAABBTreeItem(rng, items)
+				r := items[id].Add(Vec{rng.Float64()*6 - 3, rng.Float64()*6 - 3})
+				items[id] = r
+				tree.Update(id, r)
+			default:
+				r := randomRect()
+				v := rng.Int()
+				id := tree.Insert(r, v)
+				if _, ok := items[id]; ok {
+					t.Fatalf("round %d: Insert returned a live id %d", round, id)
+				}
+				items[id] = r
+				values[id] = v
+			}
+		}
+
+		if tree.Len() != len(items) {
+			t.Fatalf("round %d: Len()=%d, want %d", round, tree.Len(), len(items))
+		}
+		validateAABBTree(t, tree)
+		if h := tree.Height(); len(items) > 0 && float64(h) > 3*math.Log2(float64(len(items)))+2 {
+			t.Fatalf("round %d: tree is not balanced: height %d for %d items", round, h, len(items))
+		}
+		for id, r := range items {
+			if !rectEncloses(tree.FatBounds(id), r) {
+				t.Fatalf("round %d: item %d fat bounds %v don't contain %v", round, id, tree.FatBounds(id), r)
+			}
+			if tree.Value(id) != values[id] {
+				t.Fatalf("round %d: item %d value mismatch", round, id)
+			}
+		}
+
+		area := randomRect()
+		area.Max = area.Max.Add(Vec{50, 50})
+		ids = tree.QueryRect(ids[:0], area)
+		sort.Ints(ids)
+		var want []int
+		for id := range items {
+			if rectsTouch(tree.FatBounds(id), area) {
+				want = append(want, id)
+			}
+		}
+		sort.Ints(want)
+		if len(ids) != len(want) {
+			t.Fatalf("round %d: QueryRect:\nhave: %v\nwant: %v", round, ids, want)
+		}
+		for i := range ids {
+			if ids[i] != want[i] {
+				t.Fatalf("round %d: QueryRect:\nhave: %v\nwant: %v", round, ids, want)
+			}
+		}
+
+		pairs = tree.Pairs(pairs[:0])
+		seen := make(map[[2]int]bool)
+		for _, p := range pairs {
+			if p[0] >= p[1] || seen[p] {
+				t.Fatalf("round %d: invalid pair %v", round, p)
+			}
+			seen[p] = true
+			if !rectsTouch(tree.FatBounds(p[0]), tree.FatBounds(p[1])) {
+				t.Fatalf("round %d: non-overlapping pair %v", round, p)
+			}
+		}
+		wantPairs := 0
+		for id1 := range items {
+			for id2 := range items {
+				if id1 < id2 && rectsTouch(tree.FatBounds(id1), tree.FatBounds(id2)) {
+					wantPairs++
+				}
+			}
+		}
+		if len(pairs) != wantPairs {
+			t.Fatalf("round %d: found %d pairs, want %d", round, len(pairs), wantPairs)
+		}
+
+		// Find the closest precise hit with the ray clipping.
+		origin := Vec{rng.Float64() * 500, rng.Float64() * 500}
+		dir := RadToVec(Rad(rng.Float64() * 2 * math.Pi))
+		closest := -1
+		tree.RayCast(origin, dir, 300, func(id int, maxDist float64) float64 {
+			if dist, ok := rayRect(origin, dir, items[id]); ok && dist < maxDist {
+				closest = id
+				return dist
+			}
+			return maxDist
+		})
+		wantClosest := -1
+		bestDist := 300.0
+		for id, r := range items {
+			if dist, ok := rayRect(origin, dir, r); ok && dist <= bestDist {
+				if dist < bestDist || wantClosest == -1 {
+					wantClosest = id
+					bestDist = dist
+				}
+			}
+		}
+		if (closest == -1) != (wantClosest == -1) {
+			t.Fatalf("round %d: RayCast found %d, want %d", round, closest, wantClosest)
+		}
+		if closest != -1 {
+			dist, _ := rayRect(origin, dir, items[closest])
+			if dist != bestDist {
+				t.Fatalf("round %d: RayCast found a hit at %v, want %v", round, dist, bestDist)
+			}
+		}
+	}
+}
+
+func TestAABBTreeUpdate(t *testing.T) {
+	tree := NewAABBTree[string](1)
+	a := tree.Insert(Rect{Max: Vec{10, 10}}, "a")
+	b := tree.Insert(Rect{Min: Vec{20, 20}, Max: Vec{30, 30}}, "b")
+
+	if tree.Update(a, Rect{Min: Vec{0.5, 0.5}, Max: Vec{10.5, 10.5}}) {
+		t.Fatal("Update inside the fat bounds re-inserted the item")
+	}
+	if !tree.Update(a, Rect{Min: Vec{15, 15}, Max: Vec{25, 25}}) {
+		t.Fatal("Update outside the fat bounds didn't re-insert the item")
+	}
+	if tree.Value(a) != "a" || tree.Value(b) != "b" {
+		t.Fatal("ids changed after the update")
+	}
+	want := Rect{Min: Vec{14, 14}, Max: Vec{26, 26}}
+	if have := tree.FatBounds(a); have != want {
+		t.Fatalf("FatBounds:\nhave: %v\nwant: %v", have, want)
+	}
+	if pairs := tree.Pairs(nil); len(pairs) != 1 {
+		t.Fatalf("Pairs: %v", pairs)
+	}
+
+	tree.Remove(a)
+	tree.Remove(b)
+	if tree.Len() != 0 || tree.Height() != -1 {
+		t.Fatalf("tree is not empty: len=%d height=%d", tree.Len(), tree.Height())
+	}
+}
+
+func TestAABBTreeRayCastNestedQueries(t *testing.T) {
+	tree := NewAABBTree[int](0)
+	for i := 0; i < 20; i++ {
+		x := float64(i * 10)
+		tree.Insert(Rect{Min: Vec{x, -1}, Max: Vec{x + 5, 1}}, i)
+		tree.Insert(Rect{Min: Vec{x, 10}, Max: Vec{x + 5, 20}}, -1)
+	}
+
+	// Warm up the internal buffers, so the nested queries could reuse them.
+	buf := tree.QueryRect(nil, Rect{Min: Vec{-1000, -1000}, Max: Vec{1000, 1000}})
+	tree.RayCast(Vec{-10, 0}, Vec{1, 0}, 1000, func(id int, maxDist float64) float64 {
+		return maxDist
+	})
+
+	hits := 0
+	tree.RayCast(Vec{-10, 0}, Vec{1, 0}, 1000, func(id int, maxDist float64) float64 {
+		if tree.Value(id) == -1 {
+			t.Fatalf("unexpected hit: %d", id)
+		}
+		hits++
+		// The callback queries don't affect the outer search.
+		buf = tree.QueryRect(buf[:0], Rect{Min: Vec{-1000, -1000}, Max: Vec{1000, 1000}})
+		_ = tree.Pairs(nil)
+		tree.RayCast(Vec{-10, 15}, Vec{1, 0}, 1000, func(id int, maxDist float64) float64 {
+			return maxDist
+		})
+		return maxDist
+	})
+	if hits != 20 {
+		t.Fatalf("RayCast hits: have %d, want 20", hits)
+	}
+}
+
+func pickAABBTreeItem(rng *rand.Rand, items map[int]Rect) int {
+	ids := make([]int, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids[rng.Intn(len(ids))]
+}
+
+func validateAABBTree[T any](t *testing.T, tree *AABBTree[T]) {
+	t.Helper()
+	if tree.root == -1 {
+		return
+	}
+	if tree.nodes[tree.root].parent != -1 {
+		t.Fatal("root has a parent")
+	}
+	var validate func(index int32) int
+	validate = func(index int32) int {
+		node := &tree.nodes[index]
+		if node.child1 == -1 {
+			if node.height != 0 {
+				t.Fatalf("leaf %d has height %d", index, node.height)
+			}
+			return 1
+		}
+		child1 := &tree.nodes[node.child1]
+		child2 := &tree.nodes[node.child2]
+		if child1.parent != index || child2.parent != index {
+			t.Fatalf("node %d children have invalid parent links", index)
+		}
+		if node.height != 1+max32(child1.height, child2.height) {
+			t.Fatalf("node %d has invalid height", index)
+		}
+		if node.bounds != rectUnion(child1.bounds, child2.bounds) {
+			t.Fatalf("node %d has invalid bounds", index)
+		}
+		return validate(node.child1) + validate(node.child2)
+	}
+	if n := validate(tree.root); n != tree.Len() {
+		t.Fatalf("tree has %d leaves, want %d", n, tree.Len())
+	}
+}
@@ -0,0 +1,239 @@
+package gmath
+
+import (
+	"math"
+)
+
+// Affine2D is a 2D affine transformation matrix:
+//
+//	| a  b  tx |
+//	| c  d  ty |
+//
+// The elements layout and the operations semantics match the Ebitengine GeoM,
+// so the matrix can be copied into it element by element (see [Affine2D.Element]).
+//
+// Like with GeoM, a zero value is an identity matrix and the
+// transformation methods (like [Affine2D.Rotate]) are applied
+// after the existing ones.
+type Affine2D struct {
+	// a and d are stored with -1 offset to make the zero value an identity matrix.
+	a1 float64
+	b  float64
+	c  float64
+	d1 float64
+	tx float64
+	ty float64
+}
+
+// Affine2DFromElements creates a matrix from its elements.
+// The arguments are in the GeoM order.
+func Affine2DFromElements(a, b, c, d, tx, ty float64) Affine2D {
+	return Affine2D{
+		a1: a - 1,
+		b:  b,
+		c:  c,
+		d1: d - 1,
+		tx: tx,
+		ty: ty,
+	}
+}
+
+// Elements returns all matrix elements in the GeoM order.
+func (m Affine2D) Elements() (a, b, c, d, tx, ty float64) {
+	return m.a1 + 1, m.b, m.c, m.d1 + 1, m.tx, m.ty
+}
+
+// Element returns the matrix element at the specified row and column.
+// The row is in [0, 1] range and the column is in [0, 2] range.
+func (m Affine2D) Element(i, j int) float64 {
+	switch {
+	case i == 0 && j == 0:
+		return m.a1 + 1
+	case i == 0 && j == 1:
+		return m.b
+	case i == 0 && j == 2:
+		return m.tx
+	case i == 1 && j == 0:
+		return m.c
+	case i == 1 && j == 1:
+		return m.d1 + 1
+	case i == 1 && j == 2:
+		return m.ty
+	default:
+		panic("index out of range")
+	}
+}
+
+// SetElement sets the matrix element at the specified row and column.
+// See [Affine2D.Element].
+func (m *Affine2D) SetElement(i, j int, value float64) {
+	switch {
+	case i == 0 && j == 0:
+		m.a1 = value - 1
+	case i == 0 && j == 1:
+		m.b = value
+	case i == 0 && j == 2:
+		m.tx = value
+	case i == 1 && j == 0:
+		m.c = value
+	case i == 1 && j == 1:
+		m.d1 = value - 1
+	case i == 1 && j == 2:
+		m.ty = value
+	default:
+		panic("index out of range")
+	}
+}
+
+// Reset turns the matrix into an identity matrix.
+func (m *Affine2D) Reset() {
+	*m = Affine2D{}
+}
+
+// IsIdentity reports whether the matrix is an identity matrix.
+func (m Affine2D) IsIdentity() bool {
+	return m == Affine2D{}
+}
+
+// Translate applies the translation after the current transformation.
+func (m *Affine2D) Translate(offset Vec) {
+	m.tx += offset.X
+	m.ty += offset.Y
+}
+
+// Scale applies the scaling after the current transformation.
+func (m *Affine2D) Scale(factor Vec) {
+	a, b, c, d, tx, ty := m.Elements()
+	*m = Affine2DFromElements(
+		a*factor.X, b*factor.X,
+		c*factor.Y, d*factor.Y,
+		tx*factor.X, ty*factor.Y)
+}
+
+// Rotate applies the rotation after the current transformation.
+// The rotation direction is the same as in [Vec.Rotated].
+func (m *Affine2D) Rotate(angle Rad) {
+	if angle == 0 {
+		return
+	}
+	sin, cos := math.Sincos(float64(angle))
+	m.Concat(Affine2DFromElements(cos, -sin, sin, cos, 0, 0))
+}
+
+// Skew applies the skew transformation after the current transformation.
+// The angles semantics are identical to the GeoM.Skew.
+func (m *Affine2D) Skew(skewX, skewY Rad) {
+	m.Concat(Affine2DFromElements(1, math.Tan(float64(skewX)), math.Tan(float64(skewY)), 1, 0, 0))
+}
+
+// Concat applies the other transformation after the current one.
+// In the matrix terms, it's m = other * m.
+func (m *Affine2D) Concat(other Affine2D) {
+	*m = m.Then(other)
+}
+
+// Then returns a matrix that applies m first and then other.
+// It's like [Affine2D.Concat], but it doesn't modify m.
+func (m Affine2D) Then(other Affine2D) Affine2D {
+	a, b, c, d, tx, ty := m.Elements()
+	oa, ob, oc, od, otx, oty := other.Elements()
+	return Affine2DFromElements(
+		oa*a+ob*c,
+		oa*b+ob*d,
+		oc*a+od*c,
+		oc*b+od*d,
+		oa*tx+ob*ty+otx,
+		oc*tx+od*ty+oty)
+}
+
+// Determinant returns the determinant of the linear part of the matrix.
+// A negative determinant means that the transformation flips the orientation.
+func (m Affine2D) Determinant() float64 {
+	return (m.a1+1)*(m.d1+1) - m.b*m.c
+}
+
+// IsInvertible reports whether the matrix has an inverse.
+// The nearly singular matrices are not considered to be invertible (see [Affine2D.Inverse]).
+func (m Affine2D) IsInvertible() bool {
+	a, b, c, d, _, _ := m.Elements()
+	_, ok := det2(a, b, c, d)
+	return ok
+}
+
+// Inverse returns the inverse matrix.
+// It returns false if the matrix is not invertible.
+//
+// A matrix is treated as singular if its determinant is close to zero
+// relative to the elements magnitude: the round-off errors rarely
+// give an exact zero, so {0.1, 0.2, 0.3, 0.6} is singular too.
+func (m Affine2D) Inverse() (Affine2D, bool) {
+	a, b, c, d, tx, ty := m.Elements()
+	det, ok := det2(a, b, c, d)
+	if !ok {
+		return Affine2D{}, false
+	}
+	inv := 1 / det
+	return Affine2DFromElements(
+		d*inv,
+		-b*inv,
+		-c*inv,
+		a*inv,
+		(b*ty-d*tx)*inv,
+		(c*tx-a*ty)*inv), true
+}
+
+// Apply transforms the point.
+func (m Affine2D) Apply(v Vec) Vec {
+	return Vec{
+		X: (m.a1+1)*v.X + m.b*v.Y + m.tx,
+		Y: m.c*v.X + (m.d1+1)*v.Y + m.ty,
+	}
+}
+
+// ApplyRect transforms the rectangle corners and returns their bounding rectangle.
+func (m Affine2D) ApplyRect(r Rect) Rect {
+	corners := r.Corners()
+	result := Rect{Min: m.Apply(corners[0])}
+	result.Max = result.Min
+	for _, c := range corners[1:] {
+		result = rectUnion(result, Rect{Min: m.Apply(c), Max: m.Apply(c)})
+	}
+	return result
+}
+
+// ApplySlice transforms all points in place.
+func (m Affine2D) ApplySlice(points []Vec) {
+	for i, p := range points {
+		points[i] = m.Apply(p)
+	}
+}
+
+// Decompose extracts the translation, rotation and scale from the matrix.
+// Combining them in the scale-rotate-translate order gives the original matrix,
+// unless it contains a skew (the skew component is lost).
+//
+// A matrix that flips the orientation results in a negative Y scale.
+func (m Affine2D) Decompose() (translation Vec, rotation Rad, scale Vec) {
+	a, _, c, _, tx, ty := m.Elements()
+	translation = Vec{X: tx, Y: ty}
+	scale.X = math.Hypot(a, c)
+	if scale.X != 0 {
+		rotation = Rad(math.Atan2(c, a))
+		scale.Y = m.Determinant() / scale.X
+	} else {
+		// The X axis is collapsed; use the Y axis to restore the rotation.
+		b, d := m.b, m.d1+1
+		rotation = Rad(math.Atan2(-b, d))
+		scale.Y = math.Hypot(b, d)
+	}
+	return translation, rotation, scale
+}
+
+// det2 returns the determinant of the {{a, b}, {c, d}} matrix.
+// It returns false if the matrix is singular or nearly singular.
+func det2(a, b, c, d float64) (float64, bool) {
+	ad := a * d
+	bc := b * c
+	det := ad - bc
+	return det, math.Abs(det) > Epsilon*(math.Abs(ad)+math.Abs(bc))
+}
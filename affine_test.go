@@ -0,0 +1,140 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAffine2DIdentity(t *testing.T) {
+	var m Affine2D
+	if !m.IsIdentity() {
+		t.Fatal("zero value is not an identity matrix")
+	}
+	a, b, c, d, tx, ty := m.Elements()
+	if a != 1 || b != 0 || c != 0 || d != 1 || tx != 0 || ty != 0 {
+		t.Fatalf("unexpected identity elements: %v %v %v %v %v %v", a, b, c, d, tx, ty)
+	}
+	if have := m.Apply(Vec{3, 4}); have != (Vec{3, 4}) {
+		t.Fatalf("identity Apply: %v", have)
+	}
+
+	m = Affine2DFromElements(1, 2, 3, 4, 5, 6)
+	want := [2][3]float64{{1, 2, 5}, {3, 4, 6}}
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			if have := m.Element(i, j); have != want[i][j] {
+				t.Fatalf("Element(%d, %d): have %v, want %v", i, j, have, want[i][j])
+			}
+			var m2 Affine2D
+			m2.SetElement(i, j, 10)
+			if m2.Element(i, j) != 10 {
+				t.Fatalf("SetElement(%d, %d) failed", i, j)
+			}
+		}
+	}
+}
+
+func TestAffine2DTransform(t *testing.T) {
+	var m Affine2D
+	m.Scale(Vec{2, 3})
+	m.Rotate(math.Pi / 2)
+	m.Translate(Vec{10, 20})
+
+	// The operations are applied in the call order.
+	p := Vec{1, 1}
+	want := p.Mul(Vec{2, 3}).Rotated(math.Pi / 2).Add(Vec{10, 20})
+	if have := m.Apply(p); !have.EqualApprox(want) {
+		t.Fatalf("Apply(%v):\nhave: %v\nwant: %v", p, have, want)
+	}
+	if !EqualApprox(m.Determinant(), 6) {
+		t.Fatalf("Determinant(): have %v, want 6", m.Determinant())
+	}
+
+	inv, ok := m.Inverse()
+	if !ok {
+		t.Fatal("matrix is not invertible")
+	}
+	if have := inv.Apply(want); !have.EqualApprox(p) {
+		t.Fatalf("inverse Apply(%v):\nhave: %v\nwant: %v", want, have, p)
+	}
+	both := m.Then(inv)
+	a, b, c, d, tx, ty := both.Elements()
+	for _, v := range [][2]float64{{a, 1}, {b, 0}, {c, 0}, {d, 1}, {tx, 0}, {ty, 0}} {
+		if !EqualApprox(v[0], v[1]) {
+			t.Fatalf("m*inverse is not identity: %v", both)
+		}
+	}
+
+	singular := []Affine2D{
+		Affine2DFromElements(1, 2, 2, 4, 0, 0),
+		Affine2DFromElements(0.1, 0.2, 0.3, 0.6, 0, 0),
+		Affine2DFromElements(1, 1, 1, 1+1e-12, 0, 0),
+		Affine2DFromElements(0, 0, 0, 0, 1, 2),
+	}
+	for _, m := range singular {
+		if m.IsInvertible() {
+			t.Fatalf("singular matrix %v is invertible", m)
+		}
+		if _, ok := m.Inverse(); ok {
+			t.Fatalf("singular matrix %v has an inverse", m)
+		}
+	}
+	// A small scale is not a singularity.
+	tiny := Affine2DFromElements(1e-12, 0, 0, 1e-12, 0, 0)
+	if _, ok := tiny.Inverse(); !ok || !tiny.IsInvertible() {
+		t.Fatal("scaled down matrix is not invertible")
+	}
+
+	points := []Vec{{0, 0}, {1, 0}}
+	m.ApplySlice(points)
+	if !points[0].EqualApprox(Vec{10, 20}) || !points[1].EqualApprox(Vec{10, 22}) {
+		t.Fatalf("ApplySlice: %v", points)
+	}
+}
+
+func TestAffine2DSkew(t *testing.T) {
+	var m Affine2D
+	m.Skew(math.Pi/4, 0)
+	if have := m.Apply(Vec{0, 1}); !have.EqualApprox(Vec{1, 1}) {
+		t.Fatalf("Skew X: %v", have)
+	}
+	m.Reset()
+	m.Skew(0, math.Pi/4)
+	if have := m.Apply(Vec{1, 0}); !have.EqualApprox(Vec{1, 1}) {
+		t.Fatalf("Skew Y: %v", have)
+	}
+}
+
+func TestAffine2DApplyRect(t *testing.T) {
+	var m Affine2D
+	m.Rotate(math.Pi / 4)
+	have := m.ApplyRect(Rect{Min: Vec{-1, -1}, Max: Vec{1, 1}})
+	want := Rect{Min: Vec{-math.Sqrt2, -math.Sqrt2}, Max: Vec{math.Sqrt2, math.Sqrt2}}
+	if !have.Min.EqualApprox(want.Min) || !have.Max.EqualApprox(want.Max) {
+		t.Fatalf("ApplyRect:\nhave: %v\nwant: %v", have, want)
+	}
+}
+
+func TestAffine2DDecompose(t *testing.T) {
+	tests := []struct {
+		translation Vec
+		rotation    Rad
+		scale       Vec
+	}{
+		{Vec{}, 0, Vec{1, 1}},
+		{Vec{10, -5}, 0.5, Vec{2, 3}},
+		{Vec{1, 2}, -2, Vec{0.5, 0.5}},
+		{Vec{1, 2}, 1, Vec{2, -1}},
+		{Vec{1, 2}, 1, Vec{0, 2}},
+	}
+	for _, test := range tests {
+		var m Affine2D
+		m.Scale(test.scale)
+		m.Rotate(test.rotation)
+		m.Translate(test.translation)
+		translation, rotation, scale := m.Decompose()
+		if !translation.EqualApprox(test.translation) || !rotation.EqualApprox(test.rotation) || !scale.EqualApprox(test.scale) {
+			t.Fatalf("Decompose():\nhave: %v %v %v\nwant: %v %v %v", translation, rotation, scale, test.translation, test.rotation, test.scale)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package gmath
+
+import (
+	"math"
+)
+
+// ArcQuery is a precomputed arc sector (a vision cone) for the fast point tests.
+//
+// It has the same semantics as [ArcSectionContains], but the trigonometry
+// is evaluated only once during the query creation;
+// every point test only needs a few multiplications.
+//
+// The zero value is not usable, use [NewArcQuery] to create a query.
+type ArcQuery struct {
+	pos Vec
+
+	// dir is a normalized arc bisector direction.
+	dir Vec
+
+	cosHalf    float64
+	cosHalfSqr float64
+	radiusSqr  float64
+
+	// containsPos is a result for the pos point itself.
+	containsPos bool
+}
+
+// NewArcQuery creates an arc sector query.
+// The arguments have the same meaning as in [ArcSectionContains].
+//
+// To get the [ArcContains] semantics (an unlimited radius), use math.Inf(1) as r.
+func NewArcQuery(angle, measure Rad, r float64, pos Vec) ArcQuery {
+	// These calculations should be kept in sync with ArcContains.
+	startAngle := (angle - measure/2)
+	endAngle := (angle + measure/2)
+	if endAngle < startAngle {
+		endAngle += 2 * math.Pi
+	}
+	half := (endAngle - startAngle) / 2
+	mid := (endAngle + startAngle) / 2
+	cosHalf := math.Cos(float64(half))
+
+	sin, cos := math.Sincos(float64(mid))
+	return ArcQuery{
+		pos:        pos,
+		dir:        Vec{X: cos, Y: sin},
+		cosHalf:    cosHalf,
+		cosHalfSqr: cosHalf * cosHalf,
+		radiusSqr:  r * r,
+		// ArcContains uses a zero angle for the pos point.
+		containsPos: math.Cos(float64(-mid)) >= cosHalf,
+	}
+}
+
+// Contains reports whether the point is inside the arc sector.
+func (q ArcQuery) Contains(point Vec) bool {
+	d := point.Sub(q.pos)
+	lenSqr := d.LenSquared()
+	if lenSqr > q.radiusSqr {
+		return false
+	}
+	if lenSqr == 0 {
+		return q.containsPos
+	}
+	// The cos(angle to point - mid) >= cosHalf condition
+	// is expressed in terms of dot product: dot(d, dir) >= |d|*cosHalf.
+	// Both sides are squared to avoid the sqrt, so the signs are checked separately.
+	dot := d.Dot(q.dir)
+	if q.cosHalf >= 0 {
+		return dot >= 0 && dot*dot >= q.cosHalfSqr*lenSqr
+	}
+	return dot >= 0 || dot*dot <= q.cosHalfSqr*lenSqr
+}
+
+// FilterIndices appends the indices of the points that are inside the arc sector to dst.
+func (q ArcQuery) FilterIndices(dst []int, points []Vec) []int {
+	for i, p := range points {
+		if q.Contains(p) {
+			dst = append(dst, i)
+		}
+	}
+	return dst
+}
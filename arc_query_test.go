@@ -0,0 +1,86 @@
+package gmath
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestArcQueryMatchesArcSectionContains(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	measures := []Rad{0, 0.1, math.Pi / 4, math.Pi / 2, 2, math.Pi, 4, 2 * math.Pi, 7, -1, -math.Pi / 2}
+	for round := 0; round < 2000; round++ {
+		angle := Rad(rng.Float64()*8*math.Pi - 4*math.Pi)
+		measure := measures[rng.Intn(len(measures))]
+		if rng.Intn(2) == 0 {
+			measure = Rad(rng.Float64()*8 - 1)
+		}
+		r := rng.Float64() * 100
+		if rng.Intn(4) == 0 {
+			r = math.Inf(1)
+		}
+		pos := Vec{rng.Float64()*100 - 50, rng.Float64()*100 - 50}
+		q := NewArcQuery(angle, measure, r, pos)
+
+		points := make([]Vec, 50)
+		for i := range points {
+			points[i] = pos.Add(Vec{rng.Float64()*200 - 100, rng.Float64()*200 - 100})
+		}
+		points[0] = pos
+
+		indices := q.FilterIndices(nil, points)
+		k := 0
+		for i, p := range points {
+			want := ArcSectionContains(angle, measure, r, pos, p)
+			if math.IsInf(r, 1) {
+				want = ArcContains(angle, measure, pos, p)
+			}
+			have := k < len(indices) && indices[k] == i
+			if have {
+				k++
+			}
+			if q.Contains(p) != have {
+				t.Fatalf("FilterIndices and Contains mismatch for %v", p)
+			}
+			if have == want {
+				continue
+			}
+			// Allow the differences caused by the float precision at the arc edges.
+			if nearArcEdge(angle, measure, pos, p) {
+				continue
+			}
+			t.Fatalf("NewArcQuery(%v, %v, %v, %v).Contains(%v):\nhave: %v\nwant: %v", angle, measure, r, pos, p, have, want)
+		}
+		if k != len(indices) {
+			t.Fatalf("FilterIndices returned unexpected indices: %v", indices)
+		}
+	}
+}
+
+func TestArcQueryNoAllocs(t *testing.T) {
+	q := NewArcQuery(0, math.Pi/2, 100, Vec{})
+	points := []Vec{{10, 0}, {0, 10}, {10, 5}, {-10, 0}}
+	buf := make([]int, 0, len(points))
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = q.FilterIndices(buf[:0], points)
+	})
+	if allocs != 0 {
+		t.Fatalf("FilterIndices allocates: %v", allocs)
+	}
+	if len(buf) != 2 || buf[0] != 0 || buf[1] != 2 {
+		t.Fatalf("FilterIndices: %v", buf)
+	}
+}
+
+func nearArcEdge(angle, measure Rad, pos, point Vec) bool {
+	startAngle := (angle - measure/2)
+	endAngle := (angle + measure/2)
+	if endAngle < startAngle {
+		endAngle += 2 * math.Pi
+	}
+	half := (endAngle - startAngle) / 2
+	mid := (endAngle + startAngle) / 2
+	angleToPoint := pos.AngleToPoint(point).Normalized()
+	return math.Abs(math.Cos(float64(angleToPoint-mid))-math.Cos(float64(half))) < 1e-9
+}
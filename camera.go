@@ -0,0 +1,204 @@
+package gmath
+
+import (
+	"math"
+)
+
+// Camera2D maps the world space into the screen space.
+//
+// The camera position is a world point that is displayed at the viewport center.
+// The zoom scales the world around that point (2 makes everything twice as large)
+// and the rotation turns the camera itself: the world is rotated in the opposite direction.
+//
+// Use [NewCamera2D] to create a camera.
+type Camera2D struct {
+	pos      Vec
+	zoom     float64
+	rotation Rad
+
+	viewport Vec
+
+	// bounds is an optional world area that limits the camera movement.
+	bounds Rect
+
+	deadzoneHalf Vec
+}
+
+// NewCamera2D creates a camera for the viewport of the given size (in screen pixels).
+// The initial zoom is 1 and the camera is looking at the world origin.
+func NewCamera2D(viewportSize Vec) *Camera2D {
+	return &Camera2D{
+		zoom:     1,
+		viewport: viewportSize,
+	}
+}
+
+// Pos returns the world point the camera is centered at.
+func (c *Camera2D) Pos() Vec { return c.pos }
+
+// SetPos centers the camera at the given world point.
+// The position is clamped to the camera bounds, if they're set.
+func (c *Camera2D) SetPos(pos Vec) {
+	c.pos = pos
+	c.clamp()
+}
+
+// Move translates the camera by the world space delta.
+func (c *Camera2D) Move(delta Vec) {
+	c.SetPos(c.pos.Add(delta))
+}
+
+// Zoom returns the current zoom factor.
+func (c *Camera2D) Zoom() float64 { return c.zoom }
+
+// SetZoom changes the zoom factor while keeping the camera position.
+// The zoom must be positive.
+func (c *Camera2D) SetZoom(zoom float64) {
+	if zoom <= 0 {
+		panic("zoom must be positive")
+	}
+	c.zoom = zoom
+	c.clamp()
+}
+
+// ZoomAt changes the zoom factor while keeping the world point
+// under the given screen point in place (like a mouse wheel zoom).
+func (c *Camera2D) ZoomAt(screenPoint Vec, zoom float64) {
+	anchor := c.ScreenToWorld(screenPoint)
+	c.SetZoom(zoom)
+	c.Move(anchor.Sub(c.ScreenToWorld(screenPoint)))
+}
+
+// Rotation returns the camera rotation.
+func (c *Camera2D) Rotation() Rad { return c.rotation }
+
+// SetRotation changes the camera rotation.
+func (c *Camera2D) SetRotation(angle Rad) {
+	c.rotation = angle
+	c.clamp()
+}
+
+// ViewportSize returns the viewport size in screen pixels.
+func (c *Camera2D) ViewportSize() Vec { return c.viewport }
+
+// SetViewportSize changes the viewport size (e.g. after a window resize).
+func (c *Camera2D) SetViewportSize(size Vec) {
+	c.viewport = size
+	c.clamp()
+}
+
+// Bounds returns the world area that limits the camera movement.
+// A zero rect means that there are no limits.
+func (c *Camera2D) Bounds() Rect { return c.bounds }
+
+// SetBounds limits the camera movement, so the visible area
+// never goes outside of the given world rect.
+// If the visible area is larger than the bounds, the camera is centered at them.
+//
+// A zero rect removes the limits.
+func (c *Camera2D) SetBounds(bounds Rect) {
+	c.bounds = bounds
+	c.clamp()
+}
+
+// SetDeadzone sets the screen area size (centered at the viewport center)
+// where the [Camera2D.Follow] target can move freely without moving the camera.
+func (c *Camera2D) SetDeadzone(size Vec) {
+	c.deadzoneHalf = size.Mulf(0.5)
+}
+
+// Follow moves the camera towards the target world point.
+//
+// The camera moves only when the target leaves the deadzone (see [Camera2D.SetDeadzone]).
+// The weight is in [0, 1] range and specifies how much of the
+// distance to the deadzone border is covered during this call:
+// 1 keeps the target exactly inside the deadzone, lower values make
+// the movement smooth.
+// For a frame rate independent smoothing, use something like 1-math.Exp(-speed*dt) as weight.
+func (c *Camera2D) Follow(target Vec, weight float64) {
+	// The deadzone is defined in the screen space, so the target
+	// offset is calculated with the camera rotation and zoom applied.
+	offset := target.Sub(c.pos).Rotated(-c.rotation).Mulf(c.zoom)
+	excess := Vec{
+		X: deadzoneExcess(offset.X, c.deadzoneHalf.X),
+		Y: deadzoneExcess(offset.Y, c.deadzoneHalf.Y),
+	}
+	if excess.IsZero() {
+		return
+	}
+	delta := excess.Divf(c.zoom).Rotated(c.rotation)
+	c.Move(delta.Mulf(Clamp(weight, 0, 1)))
+}
+
+// Matrix returns a matrix that maps the world space into the screen space.
+// It can be used as a GeoM for the world objects drawing.
+func (c *Camera2D) Matrix() Affine2D {
+	var m Affine2D
+	m.Translate(c.pos.Neg())
+	m.Rotate(-c.rotation)
+	m.Scale(Vec{X: c.zoom, Y: c.zoom})
+	m.Translate(c.viewport.Mulf(0.5))
+	return m
+}
+
+// WorldToScreen converts a world point into the screen point.
+func (c *Camera2D) WorldToScreen(p Vec) Vec {
+	return p.Sub(c.pos).Rotated(-c.rotation).Mulf(c.zoom).Add(c.viewport.Mulf(0.5))
+}
+
+// ScreenToWorld converts a screen point into the world point.
+func (c *Camera2D) ScreenToWorld(p Vec) Vec {
+	return p.Sub(c.viewport.Mulf(0.5)).Divf(c.zoom).Rotated(c.rotation).Add(c.pos)
+}
+
+// WorldRectToScreen returns the screen bounding rect of the world rect.
+// Without rotation, the result matches the transformed rect exactly.
+func (c *Camera2D) WorldRectToScreen(r Rect) Rect {
+	return c.Matrix().ApplyRect(r)
+}
+
+// ScreenRectToWorld returns the world bounding rect of the screen rect.
+// Without rotation, the result matches the transformed rect exactly.
+func (c *Camera2D) ScreenRectToWorld(r Rect) Rect {
+	// The zoom is always positive, so the matrix is invertible.
+	m, _ := c.Matrix().Inverse()
+	return m.ApplyRect(r)
+}
+
+// VisibleRect returns the world area covered by the viewport.
+// For a rotated camera, it's a bounding rect of the visible area.
+func (c *Camera2D) VisibleRect() Rect {
+	return c.ScreenRectToWorld(Rect{Max: c.viewport})
+}
+
+func (c *Camera2D) clamp() {
+	if c.bounds.IsZero() {
+		return
+	}
+	// The half size of the visible area bounding rect.
+	sin, cos := math.Sincos(float64(c.rotation))
+	sin = math.Abs(sin)
+	cos = math.Abs(cos)
+	halfW := 0.5 * (c.viewport.X*cos + c.viewport.Y*sin) / c.zoom
+	halfH := 0.5 * (c.viewport.X*sin + c.viewport.Y*cos) / c.zoom
+	c.pos.X = clampCameraAxis(c.pos.X, halfW, c.bounds.Min.X, c.bounds.Max.X)
+	c.pos.Y = clampCameraAxis(c.pos.Y, halfH, c.bounds.Min.Y, c.bounds.Max.Y)
+}
+
+func clampCameraAxis(pos, half, lo, hi float64) float64 {
+	if hi-lo <= 2*half {
+		return (lo + hi) * 0.5
+	}
+	return Clamp(pos, lo+half, hi-half)
+}
+
+func deadzoneExcess(offset, half float64) float64 {
+	switch {
+	case offset > half:
+		return offset - half
+	case offset < -half:
+		return offset + half
+	default:
+		return 0
+	}
+}
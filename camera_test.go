@@ -0,0 +1,118 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCamera2DConversion(t *testing.T) {
+	cam := NewCamera2D(Vec{640, 480})
+	cam.SetPos(Vec{100, 100})
+	cam.SetZoom(2)
+	cam.SetRotation(math.Pi / 2)
+
+	tests := []struct {
+		world  Vec
+		screen Vec
+	}{
+		{Vec{100, 100}, Vec{320, 240}},
+		{Vec{110, 100}, Vec{320, 220}},
+		{Vec{100, 110}, Vec{340, 240}},
+	}
+	m := cam.Matrix()
+	for _, test := range tests {
+		if have := cam.WorldToScreen(test.world); !have.EqualApprox(test.screen) {
+			t.Fatalf("WorldToScreen(%v):\nhave: %v\nwant: %v", test.world, have, test.screen)
+		}
+		if have := m.Apply(test.world); !have.EqualApprox(test.screen) {
+			t.Fatalf("Matrix().Apply(%v):\nhave: %v\nwant: %v", test.world, have, test.screen)
+		}
+		if have := cam.ScreenToWorld(test.screen); !have.EqualApprox(test.world) {
+			t.Fatalf("ScreenToWorld(%v):\nhave: %v\nwant: %v", test.screen, have, test.world)
+		}
+	}
+
+	visible := cam.VisibleRect()
+	want := Rect{Min: Vec{-20, -60}, Max: Vec{220, 260}}
+	if !visible.Min.EqualApprox(want.Min) || !visible.Max.EqualApprox(want.Max) {
+		t.Fatalf("VisibleRect():\nhave: %v\nwant: %v", visible, want)
+	}
+
+	cam.SetRotation(0)
+	screenRect := cam.WorldRectToScreen(Rect{Min: Vec{100, 100}, Max: Vec{110, 120}})
+	want = Rect{Min: Vec{320, 240}, Max: Vec{340, 280}}
+	if !screenRect.Min.EqualApprox(want.Min) || !screenRect.Max.EqualApprox(want.Max) {
+		t.Fatalf("WorldRectToScreen():\nhave: %v\nwant: %v", screenRect, want)
+	}
+}
+
+func TestCamera2DZoomAt(t *testing.T) {
+	cam := NewCamera2D(Vec{640, 480})
+	cam.SetRotation(0.3)
+	screenPoint := Vec{100, 50}
+	anchor := cam.ScreenToWorld(screenPoint)
+	cam.ZoomAt(screenPoint, 3)
+	if cam.Zoom() != 3 {
+		t.Fatalf("Zoom(): %v", cam.Zoom())
+	}
+	if have := cam.ScreenToWorld(screenPoint); !have.EqualApprox(anchor) {
+		t.Fatalf("anchor moved:\nhave: %v\nwant: %v", have, anchor)
+	}
+}
+
+func TestCamera2DBounds(t *testing.T) {
+	cam := NewCamera2D(Vec{200, 100})
+	cam.SetBounds(Rect{Max: Vec{1000, 1000}})
+	if have := cam.Pos(); have != (Vec{100, 50}) {
+		t.Fatalf("clamped Pos(): %v", have)
+	}
+	cam.SetPos(Vec{2000, 500})
+	if have := cam.Pos(); have != (Vec{900, 500}) {
+		t.Fatalf("clamped Pos(): %v", have)
+	}
+	visible := cam.VisibleRect()
+	if visible.Max.X != 1000 {
+		t.Fatalf("visible area is out of bounds: %v", visible)
+	}
+
+	// The visible area is larger than the bounds along X.
+	cam.SetZoom(0.1)
+	if have := cam.Pos(); have != (Vec{500, 500}) {
+		t.Fatalf("zoomed out Pos(): %v", have)
+	}
+
+	cam.SetBounds(Rect{})
+	cam.SetPos(Vec{-100, -100})
+	if have := cam.Pos(); have != (Vec{-100, -100}) {
+		t.Fatalf("unbounded Pos(): %v", have)
+	}
+}
+
+func TestCamera2DFollow(t *testing.T) {
+	cam := NewCamera2D(Vec{640, 480})
+	cam.SetZoom(2)
+	cam.SetDeadzone(Vec{100, 100})
+
+	// The target is inside the deadzone (50 screen pixels = 25 world units).
+	cam.Follow(Vec{20, -20}, 1)
+	if have := cam.Pos(); have != (Vec{}) {
+		t.Fatalf("Pos() after a deadzone follow: %v", have)
+	}
+
+	cam.Follow(Vec{125, 0}, 1)
+	if have := cam.Pos(); !have.EqualApprox(Vec{100, 0}) {
+		t.Fatalf("Pos() after a snap follow: %v", have)
+	}
+
+	cam.Follow(Vec{100, 125}, 0.5)
+	if have := cam.Pos(); !have.EqualApprox(Vec{100, 50}) {
+		t.Fatalf("Pos() after a smooth follow: %v", have)
+	}
+
+	cam.SetRotation(math.Pi / 2)
+	cam.SetDeadzone(Vec{})
+	cam.Follow(Vec{0, 0}, 1)
+	if have := cam.Pos(); !have.EqualApprox(Vec{}) {
+		t.Fatalf("Pos() after a rotated follow: %v", have)
+	}
+}
@@ -0,0 +1,193 @@
+package gmath
+
+import (
+	"math"
+)
+
+// Capsule is a shape formed by a line segment extended by a radius
+// in every direction: a rectangle with two half-circles at its ends.
+//
+// A capsule with A=B is equivalent to a circle.
+type Capsule struct {
+	A Vec
+	B Vec
+
+	Radius float64
+}
+
+// Center returns the middle point of the capsule segment.
+func (c Capsule) Center() Vec {
+	return c.A.Midpoint(c.B)
+}
+
+// Bounds returns the smallest axis-aligned rectangle that contains the capsule.
+func (c Capsule) Bounds() Rect {
+	return Rect{
+		Min: Vec{X: math.Min(c.A.X, c.B.X) - c.Radius, Y: math.Min(c.A.Y, c.B.Y) - c.Radius},
+		Max: Vec{X: math.Max(c.A.X, c.B.X) + c.Radius, Y: math.Max(c.A.Y, c.B.Y) + c.Radius},
+	}
+}
+
+// Contains reports whether the point is inside the capsule.
+// The points on the capsule border are considered to be inside.
+func (c Capsule) Contains(p Vec) bool {
+	return closestPointOnSegment(c.A, c.B, p).DistanceSquaredTo(p) <= c.Radius*c.Radius
+}
+
+// ClosestPoint returns the point of the capsule that is closest to p.
+// For a point that is inside the capsule, the point itself is returned.
+func (c Capsule) ClosestPoint(p Vec) Vec {
+	s := closestPointOnSegment(c.A, c.B, p)
+	d := p.Sub(s)
+	if d.LenSquared() <= c.Radius*c.Radius {
+		return p
+	}
+	return s.Add(d.Normalized().Mulf(c.Radius))
+}
+
+// Support returns the capsule point that is the farthest in the given direction.
+// It implements the [Supporter] interface.
+func (c Capsule) Support(dir Vec) Vec {
+	p := c.A
+	if c.B.Dot(dir) > c.A.Dot(dir) {
+		p = c.B
+	}
+	return p.Add(dir.Normalized().Mulf(c.Radius))
+}
+
+// Translate moves the capsule by the given offset.
+func (c *Capsule) Translate(offset Vec) {
+	c.A = c.A.Add(offset)
+	c.B = c.B.Add(offset)
+}
+
+// IntersectsCircle reports whether the capsule and the circle overlap.
+// Like with [Rect.Intersects], the touching shapes do not intersect.
+func (c Capsule) IntersectsCircle(circle Circle) bool {
+	r := c.Radius + circle.Radius
+	return closestPointOnSegment(c.A, c.B, circle.Center).DistanceSquaredTo(circle.Center) < r*r
+}
+
+// IntersectsCapsule reports whether two capsules overlap.
+// Like with [Rect.Intersects], the touching shapes do not intersect.
+func (c Capsule) IntersectsCapsule(other Capsule) bool {
+	p1, p2 := closestPointsOnSegments(c.A, c.B, other.A, other.B)
+	r := c.Radius + other.Radius
+	return p1.DistanceSquaredTo(p2) < r*r
+}
+
+// IntersectsRect reports whether the capsule and the rectangle overlap.
+// Like with [Rect.Intersects], the touching shapes do not intersect.
+func (c Capsule) IntersectsRect(r Rect) bool {
+	if r.IsEmpty() {
+		return false
+	}
+	return segmentRectDistanceSquared(c.A, c.B, r) < c.Radius*c.Radius
+}
+
+// RayCast finds the first point where the ray hits the capsule.
+// The dir is expected to be normalized.
+// The hits that are further than maxDist from the origin are ignored.
+//
+// If the ray starts inside the capsule, it's reported as a hit
+// at the ray origin with a zero normal.
+func (c Capsule) RayCast(origin, dir Vec, maxDist float64) (RayHit, bool) {
+	if c.Contains(origin) {
+		return RayHit{Pos: origin}, true
+	}
+
+	hit := RayHit{Distance: math.Inf(1)}
+
+	// The capsule is a union of two circles and a rectangle;
+	// since the origin is outside, the closest hit is the one we need.
+	for _, center := range [2]Vec{c.A, c.B} {
+		if t, ok := rayCircle(origin, dir, center, c.Radius); ok && t < hit.Distance {
+			hit.Distance = t
+			hit.Pos = origin.Add(dir.Mulf(t))
+			hit.Normal = hit.Pos.Sub(center).Normalized()
+		}
+	}
+
+	axis := c.B.Sub(c.A).Normalized()
+	if !axis.IsZero() {
+		normal := Vec{X: -axis.Y, Y: axis.X}
+		for _, n := range [2]Vec{normal, normal.Neg()} {
+			offset := n.Mulf(c.Radius)
+			t, ok := raySegment(origin, dir, c.A.Add(offset), c.B.Add(offset))
+			if ok && t < hit.Distance {
+				hit.Distance = t
+				hit.Pos = origin.Add(dir.Mulf(t))
+				hit.Normal = n
+			}
+		}
+	}
+
+	if hit.Distance > maxDist {
+		return RayHit{}, false
+	}
+	return hit, true
+}
+
+// closestPointsOnSegments finds the closest pair of points
+// between the p1-q1 and p2-q2 segments.
+func closestPointsOnSegments(p1, q1, p2, q2 Vec) (Vec, Vec) {
+	d1 := q1.Sub(p1)
+	d2 := q2.Sub(p2)
+	r := p1.Sub(p2)
+	a := d1.LenSquared()
+	e := d2.LenSquared()
+	f := d2.Dot(r)
+
+	if a <= Epsilon && e <= Epsilon {
+		return p1, p2
+	}
+
+	var s, t float64
+	if a <= Epsilon {
+		t = Clamp(f/e, 0, 1)
+	} else {
+		c := d1.Dot(r)
+		if e <= Epsilon {
+			s = Clamp(-c/a, 0, 1)
+		} else {
+			b := d1.Dot(d2)
+			denom := a*e - b*b
+			if denom != 0 {
+				s = Clamp((b*f-c*e)/denom, 0, 1)
+			}
+			t = (b*s + f) / e
+			if t < 0 {
+				t = 0
+				s = Clamp(-c/a, 0, 1)
+			} else if t > 1 {
+				t = 1
+				s = Clamp((b-c)/a, 0, 1)
+			}
+		}
+	}
+
+	return p1.Add(d1.Mulf(s)), p2.Add(d2.Mulf(t))
+}
+
+// segmentRectDistanceSquared returns the squared distance between the a-b segment and the rectangle.
+// It's zero if the segment is inside the rectangle or crosses it.
+func segmentRectDistanceSquared(a, b Vec, r Rect) float64 {
+	if r.ClosestPoint(a) == a || r.ClosestPoint(b) == b {
+		return 0
+	}
+
+	corners := r.Corners()
+	for i, c := range corners {
+		if segmentsIntersect(a, b, c, corners[(i+1)%4]) {
+			return 0
+		}
+	}
+
+	dist := math.Min(
+		r.ClosestPoint(a).DistanceSquaredTo(a),
+		r.ClosestPoint(b).DistanceSquaredTo(b))
+	for _, c := range corners {
+		dist = math.Min(dist, closestPointOnSegment(a, b, c).DistanceSquaredTo(c))
+	}
+	return dist
+}
@@ -0,0 +1,132 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCapsuleContains(t *testing.T) {
+	c := Capsule{A: Vec{0, 0}, B: Vec{10, 0}, Radius: 2}
+
+	tests := []struct {
+		p       Vec
+		want    bool
+		closest Vec
+	}{
+		{Vec{5, 0}, true, Vec{5, 0}},
+		{Vec{5, 2}, true, Vec{5, 2}},
+		{Vec{-2, 0}, true, Vec{-2, 0}},
+		{Vec{5, 3}, false, Vec{5, 2}},
+		{Vec{-3, 0}, false, Vec{-2, 0}},
+		{Vec{13, 4}, false, Vec{11.2, 1.6}},
+	}
+	for _, test := range tests {
+		if have := c.Contains(test.p); have != test.want {
+			t.Fatalf("Contains(%v):\nhave: %v\nwant: %v", test.p, have, test.want)
+		}
+		if have := c.ClosestPoint(test.p); !have.EqualApprox(test.closest) {
+			t.Fatalf("ClosestPoint(%v):\nhave: %v\nwant: %v", test.p, have, test.closest)
+		}
+	}
+
+	want := Rect{Min: Vec{-2, -2}, Max: Vec{12, 2}}
+	if have := c.Bounds(); have != want {
+		t.Fatalf("Bounds():\nhave: %v\nwant: %v", have, want)
+	}
+}
+
+func TestCapsuleIntersects(t *testing.T) {
+	c := Capsule{A: Vec{0, 0}, B: Vec{10, 10}, Radius: 1}
+
+	circleTests := []struct {
+		circle Circle
+		want   bool
+	}{
+		{Circle{Center: Vec{5, 5}, Radius: 1}, true},
+		{Circle{Center: Vec{10, 0}, Radius: 1}, false},
+		{Circle{Center: Vec{8, 2}, Radius: 3.3}, true},
+		{Circle{Center: Vec{-3, 0}, Radius: 1.5}, false},
+		{Circle{Center: Vec{-2, 0}, Radius: 1.5}, true},
+	}
+	for _, test := range circleTests {
+		if have := c.IntersectsCircle(test.circle); have != test.want {
+			t.Fatalf("IntersectsCircle(%v):\nhave: %v\nwant: %v", test.circle, have, test.want)
+		}
+	}
+
+	capsuleTests := []struct {
+		other Capsule
+		want  bool
+	}{
+		{Capsule{A: Vec{0, 10}, B: Vec{10, 0}, Radius: 0.1}, true},
+		{Capsule{A: Vec{0, 3}, B: Vec{7, 10}, Radius: 1}, false},
+		{Capsule{A: Vec{0, 2}, B: Vec{8, 10}, Radius: 1}, true},
+		{Capsule{A: Vec{11, 11}, B: Vec{20, 20}, Radius: 1}, true},
+		{Capsule{A: Vec{13, 13}, B: Vec{20, 20}, Radius: 1}, false},
+		{Capsule{A: Vec{5, 5}, B: Vec{5, 5}, Radius: 0.1}, true},
+	}
+	for _, test := range capsuleTests {
+		if have := c.IntersectsCapsule(test.other); have != test.want {
+			t.Fatalf("IntersectsCapsule(%v):\nhave: %v\nwant: %v", test.other, have, test.want)
+		}
+		if have := test.other.IntersectsCapsule(c); have != test.want {
+			t.Fatalf("IntersectsCapsule(%v) reversed:\nhave: %v\nwant: %v", test.other, have, test.want)
+		}
+	}
+
+	rectTests := []struct {
+		r    Rect
+		want bool
+	}{
+		{Rect{Min: Vec{4, 4}, Max: Vec{6, 6}}, true},
+		{Rect{Min: Vec{-5, -5}, Max: Vec{20, 20}}, true},
+		{Rect{Min: Vec{6, 0}, Max: Vec{10, 3}}, false},
+		{Rect{Min: Vec{6, 0}, Max: Vec{10, 5.5}}, true},
+		{Rect{Min: Vec{11.5, 9}, Max: Vec{13, 11}}, false},
+		{Rect{Min: Vec{10.5, 9}, Max: Vec{13, 11}}, true},
+		{Rect{}, false},
+	}
+	for _, test := range rectTests {
+		if have := c.IntersectsRect(test.r); have != test.want {
+			t.Fatalf("IntersectsRect(%v):\nhave: %v\nwant: %v", test.r, have, test.want)
+		}
+	}
+}
+
+func TestCapsuleRayCast(t *testing.T) {
+	c := Capsule{A: Vec{0, 0}, B: Vec{10, 0}, Radius: 2}
+
+	tests := []struct {
+		origin  Vec
+		dir     Vec
+		maxDist float64
+		hit     bool
+		pos     Vec
+		normal  Vec
+	}{
+		{Vec{5, -10}, Vec{0, 1}, 100, true, Vec{5, -2}, Vec{0, -1}},
+		{Vec{5, 10}, Vec{0, -1}, 100, true, Vec{5, 2}, Vec{0, 1}},
+		{Vec{5, 10}, Vec{0, -1}, 5, false, Vec{}, Vec{}},
+		{Vec{5, 10}, Vec{0, 1}, 100, false, Vec{}, Vec{}},
+		{Vec{-10, 0}, Vec{1, 0}, 100, true, Vec{-2, 0}, Vec{-1, 0}},
+		{Vec{20, 0}, Vec{-1, 0}, 100, true, Vec{12, 0}, Vec{1, 0}},
+		{Vec{-10, 1}, Vec{1, 0}, 100, true, Vec{-math.Sqrt(3), 1}, Vec{-math.Sqrt(3) / 2, 0.5}},
+		{Vec{-10, 3}, Vec{1, 0}, 100, false, Vec{}, Vec{}},
+		{Vec{5, 0}, Vec{1, 0}, 100, true, Vec{5, 0}, Vec{}},
+	}
+	for _, test := range tests {
+		hit, ok := c.RayCast(test.origin, test.dir, test.maxDist)
+		if ok != test.hit {
+			t.Fatalf("RayCast(%v, %v):\nhave: %v\nwant: %v", test.origin, test.dir, ok, test.hit)
+		}
+		if !ok {
+			continue
+		}
+		if !hit.Pos.EqualApprox(test.pos) || !hit.Normal.EqualApprox(test.normal) {
+			t.Fatalf("RayCast(%v, %v):\nhave: %v %v\nwant: %v %v", test.origin, test.dir, hit.Pos, hit.Normal, test.pos, test.normal)
+		}
+		if !EqualApprox(hit.Distance, test.origin.DistanceTo(test.pos)) {
+			t.Fatalf("RayCast(%v, %v): distance mismatch: %v", test.origin, test.dir, hit.Distance)
+		}
+	}
+}
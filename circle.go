@@ -1,7 +1,56 @@
 package gmath
 
+// Circle is a shape defined by its center point and radius.
 type Circle struct {
 	Center Vec
 
 	Radius float64
 }
+
+// Bounds returns the smallest axis-aligned rectangle that contains the circle.
+func (c Circle) Bounds() Rect {
+	r := Vec{X: c.Radius, Y: c.Radius}
+	return Rect{Min: c.Center.Sub(r), Max: c.Center.Add(r)}
+}
+
+// Contains reports whether the point is inside the circle.
+// The points on the circle border are considered to be inside.
+func (c Circle) Contains(p Vec) bool {
+	return c.Center.DistanceSquaredTo(p) <= c.Radius*c.Radius
+}
+
+// ClosestPoint returns the point of the circle that is closest to p.
+// For a point that is inside the circle, the point itself is returned.
+func (c Circle) ClosestPoint(p Vec) Vec {
+	if c.Contains(p) {
+		return p
+	}
+	return c.Center.Add(p.Sub(c.Center).Normalized().Mulf(c.Radius))
+}
+
+// Translate moves the circle by the given offset.
+func (c *Circle) Translate(offset Vec) {
+	c.Center = c.Center.Add(offset)
+}
+
+// IntersectsCircle reports whether two circles overlap.
+// Like with [Rect.Intersects], the touching shapes do not intersect.
+func (c Circle) IntersectsCircle(other Circle) bool {
+	r := c.Radius + other.Radius
+	return c.Center.DistanceSquaredTo(other.Center) < r*r
+}
+
+// IntersectsRect reports whether the circle and the rectangle overlap.
+// Like with [Rect.Intersects], the touching shapes do not intersect.
+func (c Circle) IntersectsRect(r Rect) bool {
+	if r.IsEmpty() {
+		return false
+	}
+	return r.ClosestPoint(c.Center).DistanceSquaredTo(c.Center) < c.Radius*c.Radius
+}
+
+// Support returns the circle point that is the farthest in the given direction.
+// It implements the [Supporter] interface.
+func (c Circle) Support(dir Vec) Vec {
+	return c.Center.Add(dir.Normalized().Mulf(c.Radius))
+}
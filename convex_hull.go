@@ -0,0 +1,149 @@
+package gmath
+
+// ConvexHull returns the smallest convex polygon that contains all given points.
+// It uses Andrew's monotone chain algorithm that runs in O(n log n).
+//
+// The result always has a clockwise winding (see [Polygon.IsClockwise]).
+// The first vertex is the one with the lowest X (and lowest Y among those).
+//
+// If includeCollinear is true, the points that lie on the hull edges
+// are included into the result; otherwise only the corner points are kept.
+// The duplicated points never appear twice in the result.
+//
+// When all points are collinear, the result is degenerate:
+// it contains only the two extreme points (or all points
+// sorted along the line if includeCollinear is true).
+//
+// This function doesn't modify the points slice.
+// See [AppendConvexHull] for an allocation-free variant.
+func ConvexHull(points []Vec, includeCollinear bool) Polygon {
+	if len(points) == 0 {
+		return nil
+	}
+	sorted := make([]Vec, len(points))
+	copy(sorted, points)
+	return AppendConvexHull(make(Polygon, 0, len(points)+1), sorted, includeCollinear)
+}
+
+// AppendConvexHull is like [ConvexHull], but it appends the result to dst
+// and returns the extended slice.
+//
+// Unlike [ConvexHull], it sorts the points slice in-place.
+//
+// It doesn't allocate if dst has at least len(points)+1 free capacity.
+func AppendConvexHull(dst Polygon, points []Vec, includeCollinear bool) Polygon {
+	if len(points) == 0 {
+		return dst
+	}
+
+	sortVecs(points)
+
+	// Remove the duplicates; they would break the collinearity checks.
+	n := 1
+	for i := 1; i < len(points); i++ {
+		if points[i] != points[n-1] {
+			points[n] = points[i]
+			n++
+		}
+	}
+	points = points[:n]
+
+	if len(points) <= 2 {
+		return append(dst, points...)
+	}
+
+	// keep reports whether the a->b->c turn can be a part of the hull.
+	keep := func(a, b, c Vec) bool {
+		s := orientation(a, b, c)
+		if includeCollinear {
+			return s >= 0
+		}
+		return s > 0
+	}
+
+	base := len(dst)
+
+	// Build the lower hull (in terms of the Y-up coordinates).
+	for _, p := range points {
+		for len(dst)-base >= 2 && !keep(dst[len(dst)-2], dst[len(dst)-1], p) {
+			dst = dst[:len(dst)-1]
+		}
+		dst = append(dst, p)
+	}
+
+	if len(dst)-base == len(points) && allCollinear(points) {
+		// The lower hull took all points.
+		// This is only possible for the collinear input.
+		if includeCollinear {
+			return dst
+		}
+		return append(dst[:base], points[0], points[len(points)-1])
+	}
+
+	// Build the upper hull.
+	lowerLen := len(dst) - base
+	for i := len(points) - 2; i >= 0; i-- {
+		p := points[i]
+		for len(dst)-base > lowerLen && !keep(dst[len(dst)-2], dst[len(dst)-1], p) {
+			dst = dst[:len(dst)-1]
+		}
+		dst = append(dst, p)
+	}
+
+	// The last point is the same as the first one.
+	dst = dst[:len(dst)-1]
+
+	if !includeCollinear && len(dst)-base < 3 {
+		// All points are collinear.
+		return append(dst[:base], points[0], points[len(points)-1])
+	}
+
+	return dst
+}
+
+func allCollinear(points []Vec) bool {
+	a := points[0]
+	b := points[len(points)-1]
+	for _, p := range points[1 : len(points)-1] {
+		if orientation(a, b, p) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func vecLess(a, b Vec) bool {
+	return a.X < b.X || (a.X == b.X && a.Y < b.Y)
+}
+
+// sortVecs orders the vectors by X, then by Y.
+//
+// It's a heapsort that works on the concrete slice type:
+// unlike sort.Sort, it doesn't make the slice escape to the heap.
+func sortVecs(points []Vec) {
+	n := len(points)
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDownVecs(points, i, n)
+	}
+	for end := n - 1; end > 0; end-- {
+		points[0], points[end] = points[end], points[0]
+		siftDownVecs(points, 0, end)
+	}
+}
+
+func siftDownVecs(points []Vec, root, n int) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && vecLess(points[child], points[child+1]) {
+			child++
+		}
+		if !vecLess(points[root], points[child]) {
+			return
+		}
+		points[root], points[child] = points[child], points[root]
+		root = child
+	}
+}
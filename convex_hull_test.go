@@ -0,0 +1,129 @@
+package gmath
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	tests := []struct {
+		points           []Vec
+		want             Polygon
+		includeCollinear bool
+	}{
+		{nil, nil, false},
+		{[]Vec{{1, 1}}, Polygon{{1, 1}}, false},
+		{[]Vec{{1, 1}, {1, 1}}, Polygon{{1, 1}}, false},
+		{[]Vec{{2, 2}, {1, 1}}, Polygon{{1, 1}, {2, 2}}, false},
+
+		{[]Vec{{0, 0}, {1, 1}, {2, 2}, {3, 3}}, Polygon{{0, 0}, {3, 3}}, false},
+		{[]Vec{{0, 0}, {1, 1}, {2, 2}, {3, 3}}, Polygon{{0, 0}, {1, 1}, {2, 2}, {3, 3}}, true},
+
+		{
+			[]Vec{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {5, 5}, {2, 7}},
+			Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			false,
+		},
+		{
+			[]Vec{{0, 0}, {5, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 5}, {5, 5}},
+			Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			false,
+		},
+		{
+			[]Vec{{0, 0}, {5, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 5}, {5, 5}},
+			Polygon{{0, 0}, {5, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 5}},
+			true,
+		},
+		{
+			[]Vec{{3, 1}, {0, 0}, {3, 1}, {1, 4}, {2, 2}, {4, 4}, {0, 0}, {1, 2}, {3, 3}},
+			Polygon{{0, 0}, {3, 1}, {4, 4}, {1, 4}},
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		input := append([]Vec(nil), test.points...)
+		have := ConvexHull(input, test.includeCollinear)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Fatalf("ConvexHull(%v, %v):\nhave: %v\nwant: %v", test.points, test.includeCollinear, have, test.want)
+		}
+		if !reflect.DeepEqual(input, test.points) {
+			t.Fatalf("ConvexHull(%v, %v) modified the input", test.points, test.includeCollinear)
+		}
+		if have.Area() != 0 && !have.IsClockwise() {
+			t.Fatalf("ConvexHull(%v, %v) is not clockwise", test.points, test.includeCollinear)
+		}
+
+		buf := make(Polygon, 1, len(test.points)+2)
+		buf[0] = Vec{-1, -1}
+		have2 := AppendConvexHull(buf, input, test.includeCollinear)
+		if have2[0] != (Vec{-1, -1}) {
+			t.Fatalf("AppendConvexHull(%v, %v) overwrote dst prefix", test.points, test.includeCollinear)
+		}
+		if !reflect.DeepEqual(have2[1:], append(Polygon{}, test.want...)) {
+			t.Fatalf("AppendConvexHull(%v, %v):\nhave: %v\nwant: %v", test.points, test.includeCollinear, have2[1:], test.want)
+		}
+	}
+}
+
+func TestConvexHullContainsAll(t *testing.T) {
+	var rng Rand
+	rng.SetSeed(1)
+	for round := 0; round < 50; round++ {
+		points := make([]Vec, 30)
+		for i := range points {
+			points[i] = Vec{X: float64(rng.IntRange(-20, 20)), Y: float64(rng.IntRange(-20, 20))}
+		}
+		hull := ConvexHull(points, false)
+		if !hull.IsConvex() {
+			t.Fatalf("hull %v is not convex", hull)
+		}
+		for _, p := range points {
+			for i := range hull {
+				if orientation(hull[i], hull[(i+1)%len(hull)], p) < 0 {
+					t.Fatalf("point %v is outside of the hull %v", p, hull)
+				}
+			}
+		}
+	}
+}
+
+func TestSortVecs(t *testing.T) {
+	var rng Rand
+	rng.SetSeed(2)
+	for n := 0; n < 40; n++ {
+		points := make([]Vec, n)
+		for i := range points {
+			points[i] = Vec{X: float64(rng.IntRange(-5, 5)), Y: float64(rng.IntRange(-5, 5))}
+		}
+		want := make([]Vec, n)
+		copy(want, points)
+		sort.Slice(want, func(i, j int) bool { return vecLess(want[i], want[j]) })
+		sortVecs(points)
+		if !reflect.DeepEqual(points, want) {
+			t.Fatalf("sortVecs():\nhave: %v\nwant: %v", points, want)
+		}
+	}
+}
+
+func TestAppendConvexHullAllocs(t *testing.T) {
+	var rng Rand
+	rng.SetSeed(3)
+	points := make([]Vec, 100)
+	for i := range points {
+		points[i] = rng.Offset(-100, 100)
+	}
+	dst := make(Polygon, 0, len(points)+1)
+	allocs := testing.AllocsPerRun(100, func() {
+		// Keep the input unsorted for every run.
+		for i := len(points) - 1; i > 0; i-- {
+			j := rng.IntRange(0, i)
+			points[i], points[j] = points[j], points[i]
+		}
+		dst = AppendConvexHull(dst[:0], points, true)
+	})
+	if allocs != 0 {
+		t.Fatalf("AppendConvexHull allocates: %v allocs per run", allocs)
+	}
+}
@@ -0,0 +1,312 @@
+package gmath
+
+import (
+	"math"
+)
+
+// Supporter is implemented by the convex shapes that can be
+// used with GJK and EPA algorithms (see [GJKIntersects]).
+//
+// A new shape type only needs to implement this method
+// to be tested against any other [Supporter].
+//
+// Implemented by [Rect], [Circle], [Polygon], [Capsule] and [OBB].
+//
+// A shape can also implement an IsEmpty method (like [Polygon.IsEmpty]
+// and [Rect.IsEmpty]); the GJK functions treat an empty shape as a shape
+// that doesn't overlap anything.
+type Supporter interface {
+	// Support returns the shape point that is the farthest in the given direction.
+	// The dir is not guaranteed to be normalized.
+	Support(dir Vec) Vec
+}
+
+const (
+	gjkMaxIterations = 64
+	epaMaxIterations = 64
+
+	// gjkTolerance is a relative distance convergence threshold.
+	gjkTolerance = 1e-12
+
+	// epaTolerance is an absolute depth convergence threshold.
+	epaTolerance = 1e-9
+)
+
+// GJKIntersects reports whether two convex shapes overlap.
+//
+// Unlike the SAT-based functions (see [SATPolygons]), the touching shapes
+// are reported as overlapping: GJK can't tell them apart reliably
+// with a limited floating point precision.
+func GJKIntersects(a, b Supporter) bool {
+	if isEmptySupporter(a) || isEmptySupporter(b) {
+		return false
+	}
+	var s gjkSimplex
+	_, overlap := s.run(a, b)
+	return overlap
+}
+
+// GJKDistance returns the distance between two convex shapes
+// along with the closest points on each of them.
+//
+// For the overlapping shapes, the distance is 0 and
+// the returned points should not be used.
+// If any of the shapes is empty, the distance is +Inf.
+func GJKDistance(a, b Supporter) (dist float64, pointA, pointB Vec) {
+	if isEmptySupporter(a) || isEmptySupporter(b) {
+		return math.Inf(1), Vec{}, Vec{}
+	}
+	var s gjkSimplex
+	v, overlap := s.run(a, b)
+	if overlap {
+		return 0, Vec{}, Vec{}
+	}
+	for i := 0; i < s.n; i++ {
+		pointA = pointA.Add(s.verts[i].a.Mulf(s.weights[i]))
+		pointB = pointB.Add(s.verts[i].b.Mulf(s.weights[i]))
+	}
+	return v.Len(), pointA, pointB
+}
+
+// GJKPenetration checks two convex shapes for overlapping using GJK and
+// then calculates the penetration info with the expanding polytope algorithm (EPA).
+// It returns the penetration info and true if they overlap.
+//
+// See [Penetration] for the result semantics.
+// Like with [GJKIntersects], the touching shapes are overlapping
+// (with a near-zero depth).
+//
+// For the curved shapes like circles and capsules the result is an approximation.
+func GJKPenetration(a, b Supporter) (Penetration, bool) {
+	if isEmptySupporter(a) || isEmptySupporter(b) {
+		return Penetration{}, false
+	}
+	var s gjkSimplex
+	if _, overlap := s.run(a, b); !overlap {
+		return Penetration{}, false
+	}
+	return s.expand(a, b), true
+}
+
+// isEmptySupporter reports whether the shape has no points at all.
+func isEmptySupporter(s Supporter) bool {
+	if s, ok := s.(interface{ IsEmpty() bool }); ok {
+		return s.IsEmpty()
+	}
+	return false
+}
+
+// gjkVertex is a point of the Minkowski difference a-b
+// along with the shape points it was made from.
+type gjkVertex struct {
+	p Vec
+	a Vec
+	b Vec
+}
+
+func gjkSupport(a, b Supporter, dir Vec) gjkVertex {
+	pa := a.Support(dir)
+	pb := b.Support(dir.Neg())
+	return gjkVertex{p: pa.Sub(pb), a: pa, b: pb}
+}
+
+type gjkSimplex struct {
+	verts   [3]gjkVertex
+	weights [3]float64
+	n       int
+}
+
+// run executes the GJK over the Minkowski difference of a and b.
+//
+// It returns true if the difference contains the origin (the shapes overlap).
+// Otherwise, the simplex describes the closest features
+// and the returned vector is the closest point to the origin.
+func (s *gjkSimplex) run(a, b Supporter) (Vec, bool) {
+	s.verts[0] = gjkSupport(a, b, Vec{X: 1})
+	s.weights[0] = 1
+	s.n = 1
+	v := s.verts[0].p
+
+	for i := 0; i < gjkMaxIterations; i++ {
+		vv := v.LenSquared()
+		if vv <= Epsilon*Epsilon {
+			return v, true
+		}
+		w := gjkSupport(a, b, v.Neg())
+		// v is the closest point of the current simplex;
+		// if w is not any closer, we're done.
+		if vv-v.Dot(w.p) <= gjkTolerance*vv {
+			return v, false
+		}
+		s.verts[s.n] = w
+		s.n++
+		var inside bool
+		v, inside = s.solve()
+		if inside {
+			return v, true
+		}
+	}
+
+	return v, v.LenSquared() <= Epsilon*Epsilon
+}
+
+// solve reduces the simplex to its feature that is the closest to the origin.
+// It returns the closest point and true if the origin is inside the triangle.
+func (s *gjkSimplex) solve() (Vec, bool) {
+	switch s.n {
+	case 2:
+		return s.solveSegment(0, 1), false
+
+	case 3:
+		p0, p1, p2 := s.verts[0].p, s.verts[1].p, s.verts[2].p
+		area := p1.Sub(p0).Cross(p2.Sub(p0))
+		if area != 0 {
+			w0 := p1.Cross(p2) / area
+			w1 := p2.Cross(p0) / area
+			w2 := p0.Cross(p1) / area
+			if w0 >= 0 && w1 >= 0 && w2 >= 0 {
+				s.weights = [3]float64{w0, w1, w2}
+				return Vec{}, true
+			}
+		}
+		// The origin is outside: pick the closest edge.
+		// The solved copies are compared instead of doing
+		// a precise Voronoi region classification.
+		var best gjkSimplex
+		var bestPoint Vec
+		bestDist := math.Inf(1)
+		for _, e := range [3][2]int{{0, 2}, {1, 2}, {0, 1}} {
+			candidate := *s
+			v := candidate.solveSegment(e[0], e[1])
+			if d := v.LenSquared(); d < bestDist {
+				best = candidate
+				bestPoint = v
+				bestDist = d
+			}
+		}
+		*s = best
+		return bestPoint, false
+
+	default:
+		return s.verts[0].p, false
+	}
+}
+
+// solveSegment reduces the simplex to the i-j segment (or one of its vertices)
+// and returns its closest point to the origin.
+func (s *gjkSimplex) solveSegment(i, j int) Vec {
+	a := s.verts[i]
+	b := s.verts[j]
+	ab := b.p.Sub(a.p)
+	t := 0.0
+	if l := ab.LenSquared(); l != 0 {
+		t = -a.p.Dot(ab) / l
+	}
+	switch {
+	case t <= 0:
+		s.verts[0] = a
+		s.weights[0] = 1
+		s.n = 1
+		return a.p
+	case t >= 1:
+		s.verts[0] = b
+		s.weights[0] = 1
+		s.n = 1
+		return b.p
+	default:
+		s.verts[0] = a
+		s.verts[1] = b
+		s.weights[0] = 1 - t
+		s.weights[1] = t
+		s.n = 2
+		return a.p.Add(ab.Mulf(t))
+	}
+}
+
+// expand runs the EPA over a simplex that contains the origin.
+func (s *gjkSimplex) expand(a, b Supporter) Penetration {
+	// The GJK may stop with a degenerate simplex when the origin
+	// is on its vertex or edge; it needs to be a proper triangle.
+	if !s.blowUp(a, b) {
+		return Penetration{Normal: Vec{X: 1}}
+	}
+
+	polytope := make([]Vec, 3, 16)
+	for i := range polytope {
+		polytope[i] = s.verts[i].p
+	}
+	// The outward normal direction depends on the polytope winding.
+	clockwise := polytope[1].Sub(polytope[0]).Cross(polytope[2].Sub(polytope[0])) > 0
+
+	var result Penetration
+	for i := 0; i < epaMaxIterations; i++ {
+		index := -1
+		result.Depth = math.Inf(1)
+		for j, p := range polytope {
+			e := polytope[(j+1)%len(polytope)].Sub(p)
+			n := Vec{X: -e.Y, Y: e.X}
+			if clockwise {
+				n = n.Neg()
+			}
+			n = n.Normalized()
+			if n.IsZero() {
+				continue
+			}
+			if d := n.Dot(p); d < result.Depth {
+				index = j
+				result.Depth = d
+				result.Normal = n
+			}
+		}
+		if index == -1 {
+			break
+		}
+
+		w := gjkSupport(a, b, result.Normal).p
+		if w.Dot(result.Normal)-result.Depth <= epaTolerance {
+			break
+		}
+		polytope = append(polytope, Vec{})
+		copy(polytope[index+2:], polytope[index+1:])
+		polytope[index+1] = w
+	}
+
+	if result.Depth < 0 {
+		result.Depth = 0
+	}
+	return result
+}
+
+// blowUp turns a point or a segment simplex into a triangle.
+// It returns false if the Minkowski difference has no area.
+func (s *gjkSimplex) blowUp(a, b Supporter) bool {
+	if s.n == 1 {
+		for _, dir := range [4]Vec{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}} {
+			w := gjkSupport(a, b, dir)
+			if w.p.DistanceSquaredTo(s.verts[0].p) > Epsilon*Epsilon {
+				s.verts[1] = w
+				s.n = 2
+				break
+			}
+		}
+		if s.n == 1 {
+			return false
+		}
+	}
+	if s.n == 2 {
+		e := s.verts[1].p.Sub(s.verts[0].p)
+		perp := Vec{X: -e.Y, Y: e.X}
+		for _, dir := range [2]Vec{perp, perp.Neg()} {
+			w := gjkSupport(a, b, dir)
+			if math.Abs(w.p.Sub(s.verts[0].p).Cross(e)) > Epsilon*e.Len() {
+				s.verts[2] = w
+				s.n = 3
+				break
+			}
+		}
+		if s.n == 2 {
+			return false
+		}
+	}
+	return true
+}
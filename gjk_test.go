@@ -0,0 +1,204 @@
+package gmath
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGJKIntersects(t *testing.T) {
+	diamond := OBB{HalfExtents: Vec{1, 1}, Rotation: math.Pi / 4}
+
+	tests := []struct {
+		a    Supporter
+		b    Supporter
+		want bool
+	}{
+		{Rect{Max: Vec{10, 10}}, Rect{Min: Vec{5, 5}, Max: Vec{15, 15}}, true},
+		{Rect{Max: Vec{10, 10}}, Rect{Min: Vec{11, 0}, Max: Vec{15, 15}}, false},
+		{Rect{Max: Vec{10, 10}}, Rect{Min: Vec{2, 2}, Max: Vec{3, 3}}, true},
+		{Rect{Max: Vec{10, 10}}, Circle{Center: Vec{12, 5}, Radius: 2.5}, true},
+		{Rect{Max: Vec{10, 10}}, Circle{Center: Vec{13, 14}, Radius: 4.9}, false},
+		{Rect{Max: Vec{10, 10}}, Circle{Center: Vec{13, 14}, Radius: 5.1}, true},
+		{Circle{Center: Vec{0, 0}, Radius: 1}, Circle{Center: Vec{1.9, 0}, Radius: 1}, true},
+		{Circle{Center: Vec{0, 0}, Radius: 1}, Circle{Center: Vec{2.1, 0}, Radius: 1}, false},
+		{Circle{Center: Vec{3, 3}, Radius: 1}, Circle{Center: Vec{3, 3}, Radius: 2}, true},
+		{Polygon{{0, 0}, {10, 0}, {5, 10}}, Circle{Center: Vec{5, 3}, Radius: 1}, true},
+		{Polygon{{0, 0}, {10, 0}, {5, 10}}, Polygon{{8, 8}, {12, 8}, {10, 12}}, false},
+		{Polygon{{0, 0}, {10, 0}, {5, 10}}, Polygon{{6, 6}, {12, 8}, {10, 12}}, true},
+		{Capsule{A: Vec{0, 0}, B: Vec{10, 10}, Radius: 1}, Circle{Center: Vec{10, 0}, Radius: 1}, false},
+		{Capsule{A: Vec{0, 0}, B: Vec{10, 10}, Radius: 1}, Circle{Center: Vec{8, 2}, Radius: 3.3}, true},
+		{Capsule{A: Vec{0, 0}, B: Vec{10, 10}, Radius: 1}, Rect{Min: Vec{6, 0}, Max: Vec{10, 3}}, false},
+		{Capsule{A: Vec{0, 0}, B: Vec{10, 10}, Radius: 1}, Rect{Min: Vec{6, 0}, Max: Vec{10, 5.5}}, true},
+		{diamond, Rect{Min: Vec{1, 1}, Max: Vec{2, 2}}, false},
+		{diamond, Rect{Min: Vec{0.6, 0.6}, Max: Vec{2, 2}}, true},
+		{diamond, Circle{Center: Vec{1, 1}, Radius: 0.2}, false},
+		{diamond, Circle{Center: Vec{1, 1}, Radius: 0.8}, true},
+	}
+
+	for _, test := range tests {
+		if have := GJKIntersects(test.a, test.b); have != test.want {
+			t.Fatalf("GJKIntersects(%v, %v):\nhave: %v\nwant: %v", test.a, test.b, have, test.want)
+		}
+		if have := GJKIntersects(test.b, test.a); have != test.want {
+			t.Fatalf("GJKIntersects(%v, %v) reversed:\nhave: %v\nwant: %v", test.b, test.a, have, test.want)
+		}
+		dist, _, _ := GJKDistance(test.a, test.b)
+		if (dist == 0) != test.want {
+			t.Fatalf("GJKDistance(%v, %v): unexpected distance %v", test.a, test.b, dist)
+		}
+	}
+}
+
+func TestGJKDistance(t *testing.T) {
+	tests := []struct {
+		a      Supporter
+		b      Supporter
+		dist   float64
+		pointA Vec
+		pointB Vec
+	}{
+		{Rect{Max: Vec{10, 10}}, Polygon{{12, 5}, {15, 3}, {15, 7}}, 2, Vec{10, 5}, Vec{12, 5}},
+		{Rect{Max: Vec{10, 10}}, Rect{Min: Vec{13, 14}, Max: Vec{15, 16}}, 5, Vec{10, 10}, Vec{13, 14}},
+		{Rect{Max: Vec{10, 10}}, Circle{Center: Vec{13, 14}, Radius: 2}, 3, Vec{10, 10}, Vec{11.8, 12.4}},
+		{Circle{Center: Vec{0, 0}, Radius: 1}, Circle{Center: Vec{0, 5}, Radius: 2}, 2, Vec{0, 1}, Vec{0, 3}},
+		{Capsule{A: Vec{0, 0}, B: Vec{10, 0}, Radius: 1}, Polygon{{4, 3}, {6, 3}, {5, 5}}, 2, Vec{5, 1}, Vec{5, 3}},
+	}
+
+	const tolerance = 1e-6
+	for _, test := range tests {
+		dist, pointA, pointB := GJKDistance(test.a, test.b)
+		if math.Abs(dist-test.dist) > tolerance {
+			t.Fatalf("GJKDistance(%v, %v):\nhave: %v\nwant: %v", test.a, test.b, dist, test.dist)
+		}
+		if pointA.DistanceTo(test.pointA) > tolerance || pointB.DistanceTo(test.pointB) > tolerance {
+			t.Fatalf("GJKDistance(%v, %v) points:\nhave: %v %v\nwant: %v %v", test.a, test.b, pointA, pointB, test.pointA, test.pointB)
+		}
+	}
+}
+
+func TestGJKPenetration(t *testing.T) {
+	tests := []struct {
+		a      Supporter
+		b      Supporter
+		normal Vec
+		depth  float64
+	}{
+		{Rect{Max: Vec{10, 10}}, Rect{Min: Vec{7, 8}, Max: Vec{20, 20}}, Vec{0, 1}, 2},
+		{Rect{Max: Vec{10, 10}}, Rect{Min: Vec{-3, 2}, Max: Vec{1, 8}}, Vec{-1, 0}, 1},
+		{Circle{Center: Vec{0, 0}, Radius: 1}, Circle{Center: Vec{1.5, 0}, Radius: 1}, Vec{1, 0}, 0.5},
+		{Circle{Center: Vec{0, 0}, Radius: 2}, Circle{Center: Vec{0, -3}, Radius: 2}, Vec{0, -1}, 1},
+		{Rect{Max: Vec{10, 10}}, Circle{Center: Vec{13, 14}, Radius: 6}, Vec{0.6, 0.8}, 1},
+		{Capsule{A: Vec{0, 0}, B: Vec{10, 0}, Radius: 1}, Rect{Min: Vec{4, 0.5}, Max: Vec{6, 3}}, Vec{0, 1}, 0.5},
+	}
+
+	// The normals for the curved shapes are less precise than the depths.
+	const tolerance = 1e-6
+	const normalTolerance = 1e-4
+	for _, test := range tests {
+		have, ok := GJKPenetration(test.a, test.b)
+		if !ok {
+			t.Fatalf("GJKPenetration(%v, %v): no overlap", test.a, test.b)
+		}
+		if have.Normal.DistanceTo(test.normal) > normalTolerance || math.Abs(have.Depth-test.depth) > tolerance {
+			t.Fatalf("GJKPenetration(%v, %v):\nhave: %v %v\nwant: %v %v", test.a, test.b, have.Normal, have.Depth, test.normal, test.depth)
+		}
+	}
+
+	if _, ok := GJKPenetration(Rect{Max: Vec{1, 1}}, Rect{Min: Vec{2, 2}, Max: Vec{3, 3}}); ok {
+		t.Fatalf("GJKPenetration: separated rects overlap")
+	}
+}
+
+func TestGJKMatchesSAT(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	randomPolygon := func() Polygon {
+		center := Vec{rng.Float64() * 20, rng.Float64() * 20}
+		points := make([]Vec, 3+rng.Intn(6))
+		for i := range points {
+			points[i] = center.Add(Vec{rng.Float64()*10 - 5, rng.Float64()*10 - 5})
+		}
+		return ConvexHull(points, false)
+	}
+
+	for i := 0; i < 500; i++ {
+		a := randomPolygon()
+		b := randomPolygon()
+		if a.Area() < 1 || b.Area() < 1 {
+			continue
+		}
+		want, overlap := SATPolygons(a, b)
+		if overlap && want.Depth < 1e-6 {
+			// Touching polygons are treated differently.
+			continue
+		}
+		have, ok := GJKPenetration(a, b)
+		if ok != overlap {
+			t.Fatalf("GJKPenetration(%v, %v):\nhave: %v\nwant: %v", a, b, ok, overlap)
+		}
+		if !ok {
+			dist, pointA, pointB := GJKDistance(a, b)
+			if !EqualApprox(dist, pointA.DistanceTo(pointB)) {
+				t.Fatalf("GJKDistance(%v, %v): %v != %v", a, b, dist, pointA.DistanceTo(pointB))
+			}
+			continue
+		}
+		if math.Abs(have.Depth-want.Depth) > 1e-6 {
+			t.Fatalf("GJKPenetration(%v, %v) depth:\nhave: %v\nwant: %v", a, b, have.Depth, want.Depth)
+		}
+	}
+}
+
+func TestGJKEmptyPolygon(t *testing.T) {
+	shapes := []Supporter{
+		OBB{},
+		Circle{Radius: 1},
+		Rect{Min: Vec{-1, -1}, Max: Vec{1, 1}},
+		Polygon{{-1, -1}, {1, -1}, {0, 1}},
+		Polygon(nil),
+	}
+	empty := []Supporter{Polygon(nil), Polygon{}, &Polygon{}}
+	for _, a := range shapes {
+		for _, b := range empty {
+			if GJKIntersects(a, b) || GJKIntersects(b, a) {
+				t.Fatalf("GJKIntersects(%v, %v) reported an overlap", a, b)
+			}
+			if _, ok := GJKPenetration(a, b); ok {
+				t.Fatalf("GJKPenetration(%v, %v) reported an overlap", a, b)
+			}
+			if dist, _, _ := GJKDistance(a, b); !math.IsInf(dist, 1) {
+				t.Fatalf("GJKDistance(%v, %v): have %v, want +Inf", a, b, dist)
+			}
+		}
+	}
+	if have := Polygon(nil).Support(Vec{1, 0}); have != (Vec{}) {
+		t.Fatalf("empty polygon Support: %v", have)
+	}
+}
+
+type emptyTestSupporter struct{}
+
+func (emptyTestSupporter) Support(dir Vec) Vec { return Vec{} }
+
+func (emptyTestSupporter) IsEmpty() bool { return true }
+
+func TestGJKEmptySupporter(t *testing.T) {
+	shapes := []Supporter{
+		Circle{Radius: 1},
+		Rect{Min: Vec{-1, -1}, Max: Vec{1, 1}},
+	}
+	empty := []Supporter{emptyTestSupporter{}, Rect{}, Rect{Max: Vec{0, 10}}}
+	for _, a := range shapes {
+		for _, b := range empty {
+			if GJKIntersects(a, b) || GJKIntersects(b, a) {
+				t.Fatalf("GJKIntersects(%v, %v) reported an overlap", a, b)
+			}
+			if dist, _, _ := GJKDistance(a, b); !math.IsInf(dist, 1) {
+				t.Fatalf("GJKDistance(%v, %v): have %v, want +Inf", a, b, dist)
+			}
+		}
+	}
+	if !Polygon(nil).IsEmpty() || (Polygon{{1, 1}}).IsEmpty() {
+		t.Fatal("Polygon.IsEmpty mismatch")
+	}
+}
@@ -0,0 +1,274 @@
+package gmath
+
+import (
+	"math"
+)
+
+// Hex is a hexagonal grid cell expressed in the axial coordinates:
+// X is the q (column) axis and Y is the r (row) axis.
+//
+// The third cube coordinate is implied: s = -q - r (see [Hex.S] and [Hex.Cube]).
+//
+// The coordinates are layout-agnostic; use [HexLayout] to map them into the pixels.
+type Hex Ivec[int]
+
+// HexFromCube creates a hex from its cube coordinates.
+// It panics if the q+r+s=0 constraint doesn't hold.
+func HexFromCube(q, r, s int) Hex {
+	if q+r+s != 0 {
+		panic("q+r+s must be zero")
+	}
+	return Hex{X: q, Y: r}
+}
+
+// hexDirections are listed in the clockwise order (on the screen)
+// for both pointy-top and flat-top layouts.
+var hexDirections = [6]Hex{
+	{X: 1, Y: 0},
+	{X: 0, Y: 1},
+	{X: -1, Y: 1},
+	{X: -1, Y: 0},
+	{X: 0, Y: -1},
+	{X: 1, Y: -1},
+}
+
+// HexDirection returns a unit hex offset for the given direction.
+// The directions are numbered from 0 to 5 in the clockwise order;
+// other values are wrapped around.
+//
+// For the pointy-top layout, direction 0 points to the right (East).
+// For the flat-top layout, it points to the bottom-right (South-East).
+func HexDirection(dir int) Hex {
+	dir %= 6
+	if dir < 0 {
+		dir += 6
+	}
+	return hexDirections[dir]
+}
+
+// S returns the implied third cube coordinate.
+func (h Hex) S() int { return -h.X - h.Y }
+
+// Cube returns the cube coordinates of the hex.
+func (h Hex) Cube() (q, r, s int) { return h.X, h.Y, h.S() }
+
+func (h Hex) Add(other Hex) Hex {
+	return Hex{X: h.X + other.X, Y: h.Y + other.Y}
+}
+
+func (h Hex) Sub(other Hex) Hex {
+	return Hex{X: h.X - other.X, Y: h.Y - other.Y}
+}
+
+// Scale multiplies the hex coordinates by k.
+func (h Hex) Scale(k int) Hex {
+	return Hex{X: h.X * k, Y: h.Y * k}
+}
+
+// Neighbor returns an adjacent hex in the given direction.
+// See [HexDirection] for the directions numbering.
+func (h Hex) Neighbor(dir int) Hex {
+	return h.Add(HexDirection(dir))
+}
+
+// Neighbors returns all 6 adjacent hexes in the [HexDirection] order.
+func (h Hex) Neighbors() [6]Hex {
+	var result [6]Hex
+	for i, d := range hexDirections {
+		result[i] = h.Add(d)
+	}
+	return result
+}
+
+// Len returns the distance from the grid origin to the hex (in steps).
+func (h Hex) Len() int {
+	return (Iabs(h.X) + Iabs(h.Y) + Iabs(h.S())) / 2
+}
+
+// DistanceTo returns the minimal number of steps between the two hexes.
+func (h Hex) DistanceTo(other Hex) int {
+	return h.Sub(other).Len()
+}
+
+// RotatedAround rotates h around the center hex by steps*60 degrees.
+// Positive steps rotate clockwise on the screen (like a positive [Rad]),
+// negative steps rotate counter-clockwise.
+func (h Hex) RotatedAround(center Hex, steps int) Hex {
+	steps %= 6
+	if steps < 0 {
+		steps += 6
+	}
+	q, r, s := h.Sub(center).Cube()
+	for i := 0; i < steps; i++ {
+		q, r, s = -r, -s, -q
+	}
+	return center.Add(HexFromCube(q, r, s))
+}
+
+// LineTo appends the hexes that form a line between h and other to dst.
+// Both h and other are included.
+func (h Hex) LineTo(dst []Hex, other Hex) []Hex {
+	n := h.DistanceTo(other)
+	if n == 0 {
+		return append(dst, h)
+	}
+	// A small nudge is needed to break the ties consistently
+	// when the line goes exactly between the two hexes.
+	const nudge = 1e-6
+	from := Vec{X: float64(h.X) + nudge, Y: float64(h.Y) + nudge}
+	to := Vec{X: float64(other.X) + nudge, Y: float64(other.Y) + nudge}
+	step := 1.0 / float64(n)
+	for i := 0; i <= n; i++ {
+		dst = append(dst, HexRound(from.LinearInterpolate(to, step*float64(i))))
+	}
+	return dst
+}
+
+// Ring appends the hexes that are exactly radius steps away from h to dst.
+// The hexes are listed in the clockwise order.
+// A zero radius ring is h itself.
+func (h Hex) Ring(dst []Hex, radius int) []Hex {
+	if radius <= 0 {
+		return append(dst, h)
+	}
+	current := h.Add(HexDirection(4).Scale(radius))
+	for dir := 0; dir < 6; dir++ {
+		for j := 0; j < radius; j++ {
+			dst = append(dst, current)
+			current = current.Neighbor(dir)
+		}
+	}
+	return dst
+}
+
+// Spiral appends the hexes that are within radius steps from h to dst.
+// The hexes are ordered ring by ring, starting from h itself.
+// See [Hex.Ring].
+func (h Hex) Spiral(dst []Hex, radius int) []Hex {
+	for i := 0; i <= radius; i++ {
+		dst = h.Ring(dst, i)
+	}
+	return dst
+}
+
+// Range appends the hexes that are within radius steps from h to dst.
+// It's like [Hex.Spiral], but the hexes are ordered by their coordinates.
+func (h Hex) Range(dst []Hex, radius int) []Hex {
+	for q := -radius; q <= radius; q++ {
+		rmin := ClampMin(-radius, -q-radius)
+		rmax := ClampMax(radius, -q+radius)
+		for r := rmin; r <= rmax; r++ {
+			dst = append(dst, h.Add(Hex{X: q, Y: r}))
+		}
+	}
+	return dst
+}
+
+// HexRound returns a hex that contains the fractional axial coordinates.
+// The X and Y of the vector are q and r respectively.
+func HexRound(frac Vec) Hex {
+	fq := frac.X
+	fr := frac.Y
+	fs := -fq - fr
+	q := math.Round(fq)
+	r := math.Round(fr)
+	s := math.Round(fs)
+	dq := math.Abs(q - fq)
+	dr := math.Abs(r - fr)
+	ds := math.Abs(s - fs)
+	// The coordinate with the largest rounding error is restored
+	// from the other two to keep the q+r+s=0 constraint.
+	switch {
+	case dq > dr && dq > ds:
+		q = -r - s
+	case dr > ds:
+		r = -q - s
+	}
+	return Hex{X: int(q), Y: int(r)}
+}
+
+// HexOrientation specifies the hex grid layout orientation.
+type HexOrientation int
+
+const (
+	// HexPointyTop orients the hexes with a vertex on top;
+	// the rows are horizontal and every other row is shifted.
+	HexPointyTop HexOrientation = iota
+
+	// HexFlatTop orients the hexes with an edge on top;
+	// the columns are vertical and every other column is shifted.
+	HexFlatTop
+)
+
+// HexLayout maps the hex coordinates into the pixels and back.
+type HexLayout struct {
+	Orientation HexOrientation
+
+	// Size is the distance from the hex center to its corners.
+	// The X and Y are usually equal, but they can be different
+	// to get the squashed hexes.
+	Size Vec
+
+	// Origin is the pixel position of the {0, 0} hex center.
+	Origin Vec
+}
+
+// HexToPixel returns the pixel position of the hex center.
+func (l HexLayout) HexToPixel(h Hex) Vec {
+	q := float64(h.X)
+	r := float64(h.Y)
+	var x, y float64
+	if l.Orientation == HexFlatTop {
+		x = 1.5 * q
+		y = sqrt3*0.5*q + sqrt3*r
+	} else {
+		x = sqrt3*q + sqrt3*0.5*r
+		y = 1.5 * r
+	}
+	return Vec{
+		X: l.Origin.X + x*l.Size.X,
+		Y: l.Origin.Y + y*l.Size.Y,
+	}
+}
+
+// PixelToFracHex converts the pixel position into the fractional axial coordinates.
+// The result can be rounded with [HexRound].
+func (l HexLayout) PixelToFracHex(pos Vec) Vec {
+	x := (pos.X - l.Origin.X) / l.Size.X
+	y := (pos.Y - l.Origin.Y) / l.Size.Y
+	if l.Orientation == HexFlatTop {
+		return Vec{
+			X: (2.0 / 3.0) * x,
+			Y: -(1.0/3.0)*x + (sqrt3/3)*y,
+		}
+	}
+	return Vec{
+		X: (sqrt3/3)*x - (1.0/3.0)*y,
+		Y: (2.0 / 3.0) * y,
+	}
+}
+
+// PixelToHex returns a hex that contains the pixel position.
+func (l HexLayout) PixelToHex(pos Vec) Hex {
+	return HexRound(l.PixelToFracHex(pos))
+}
+
+// HexCorners returns the hex polygon vertices in the clockwise order.
+func (l HexLayout) HexCorners(h Hex) [6]Vec {
+	center := l.HexToPixel(h)
+	startAngle := -math.Pi / 6
+	if l.Orientation == HexFlatTop {
+		startAngle = 0
+	}
+	var result [6]Vec
+	for i := range result {
+		sin, cos := math.Sincos(startAngle + float64(i)*(math.Pi/3))
+		result[i] = Vec{
+			X: center.X + cos*l.Size.X,
+			Y: center.Y + sin*l.Size.Y,
+		}
+	}
+	return result
+}
+
+const sqrt3 = 1.7320508075688772
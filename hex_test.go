@@ -0,0 +1,175 @@
+package gmath
+
+import (
+	"testing"
+)
+
+func TestHexDistance(t *testing.T) {
+	tests := []struct {
+		a, b Hex
+		want int
+	}{
+		{Hex{0, 0}, Hex{0, 0}, 0},
+		{Hex{0, 0}, Hex{1, 0}, 1},
+		{Hex{0, 0}, Hex{1, -1}, 1},
+		{Hex{0, 0}, Hex{2, -1}, 2},
+		{Hex{-1, 3}, Hex{2, -1}, 4},
+	}
+	for _, test := range tests {
+		if have := test.a.DistanceTo(test.b); have != test.want {
+			t.Fatalf("DistanceTo(%v, %v): have %d, want %d", test.a, test.b, have, test.want)
+		}
+	}
+
+	h := Hex{3, -2}
+	for i, n := range h.Neighbors() {
+		if h.DistanceTo(n) != 1 {
+			t.Fatalf("neighbor %d %v is not adjacent", i, n)
+		}
+		if n != h.Neighbor(i) || n != h.Neighbor(i+6) || n != h.Neighbor(i-6) {
+			t.Fatalf("Neighbor(%d) mismatch", i)
+		}
+	}
+	if q, r, s := h.Cube(); q+r+s != 0 || HexFromCube(q, r, s) != h {
+		t.Fatalf("Cube(): %d %d %d", q, r, s)
+	}
+}
+
+func TestHexFromCubeInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("HexFromCube(1, 1, 1) didn't panic")
+		}
+	}()
+	HexFromCube(1, 1, 1)
+}
+
+func TestHexRotatedAround(t *testing.T) {
+	center := Hex{2, 2}
+	h := center.Add(Hex{1, 0})
+	for i := 0; i < 6; i++ {
+		want := center.Neighbor(i)
+		if have := h.RotatedAround(center, i); have != want {
+			t.Fatalf("RotatedAround(%d): have %v, want %v", i, have, want)
+		}
+		if have := h.RotatedAround(center, i-6); have != want {
+			t.Fatalf("RotatedAround(%d): have %v, want %v", i-6, have, want)
+		}
+	}
+	h = Hex{5, -1}
+	if have := h.RotatedAround(center, 3).RotatedAround(center, -3); have != h {
+		t.Fatalf("rotation round trip: %v", have)
+	}
+}
+
+func TestHexRingSpiralRange(t *testing.T) {
+	center := Hex{1, -3}
+	for radius := 0; radius <= 4; radius++ {
+		ring := center.Ring(nil, radius)
+		wantLen := 6 * radius
+		if radius == 0 {
+			wantLen = 1
+		}
+		if len(ring) != wantLen {
+			t.Fatalf("Ring(%d) len: have %d, want %d", radius, len(ring), wantLen)
+		}
+		for i, h := range ring {
+			if h.DistanceTo(center) != radius {
+				t.Fatalf("Ring(%d): %v is not on the ring", radius, h)
+			}
+			if radius > 0 && h.DistanceTo(ring[(i+1)%len(ring)]) != 1 {
+				t.Fatalf("Ring(%d): %v and its successor are not adjacent", radius, h)
+			}
+		}
+
+		spiral := center.Spiral(nil, radius)
+		inRange := center.Range(nil, radius)
+		wantLen = 1 + 3*radius*(radius+1)
+		if len(spiral) != wantLen || len(inRange) != wantLen {
+			t.Fatalf("Spiral/Range(%d) len: have %d/%d, want %d", radius, len(spiral), len(inRange), wantLen)
+		}
+		set := make(map[Hex]struct{})
+		for _, h := range spiral {
+			set[h] = struct{}{}
+		}
+		for _, h := range inRange {
+			if h.DistanceTo(center) > radius {
+				t.Fatalf("Range(%d): %v is too far", radius, h)
+			}
+			if _, ok := set[h]; !ok {
+				t.Fatalf("Range(%d): %v is not in the spiral", radius, h)
+			}
+		}
+	}
+}
+
+func TestHexLine(t *testing.T) {
+	a := Hex{0, 0}
+	b := Hex{4, -2}
+	line := a.LineTo(nil, b)
+	if len(line) != 5 || line[0] != a || line[len(line)-1] != b {
+		t.Fatalf("LineTo(): %v", line)
+	}
+	for i := 1; i < len(line); i++ {
+		if line[i].DistanceTo(line[i-1]) != 1 {
+			t.Fatalf("LineTo(): %v and %v are not adjacent", line[i-1], line[i])
+		}
+	}
+	if line := a.LineTo(nil, a); len(line) != 1 || line[0] != a {
+		t.Fatalf("LineTo() same hex: %v", line)
+	}
+}
+
+func TestHexLayout(t *testing.T) {
+	layouts := []HexLayout{
+		{Orientation: HexPointyTop, Size: Vec{10, 10}, Origin: Vec{100, 50}},
+		{Orientation: HexFlatTop, Size: Vec{10, 10}, Origin: Vec{100, 50}},
+		{Orientation: HexFlatTop, Size: Vec{12, 8}},
+	}
+	for _, l := range layouts {
+		center := Hex{0, 0}
+		for _, h := range center.Spiral(nil, 3) {
+			p := l.HexToPixel(h)
+			if have := l.PixelToHex(p); have != h {
+				t.Fatalf("PixelToHex(HexToPixel(%v)): have %v", h, have)
+			}
+			// The points close to the corners still belong to the hex.
+			for _, c := range l.HexCorners(h) {
+				inner := p.LinearInterpolate(c, 0.9)
+				if have := l.PixelToHex(inner); have != h {
+					t.Fatalf("PixelToHex(%v): have %v, want %v", inner, have, h)
+				}
+			}
+		}
+	}
+
+	pointy := layouts[0]
+	if have := pointy.HexToPixel(Hex{1, 0}); !have.EqualApprox(Vec{100 + 10*sqrt3, 50}) {
+		t.Fatalf("pointy-top East neighbor: %v", have)
+	}
+	flat := layouts[1]
+	if have := flat.HexToPixel(Hex{0, 1}); !have.EqualApprox(Vec{100, 50 + 10*sqrt3}) {
+		t.Fatalf("flat-top South neighbor: %v", have)
+	}
+	corners := pointy.HexCorners(Hex{})
+	if !corners[0].EqualApprox(Vec{100 + 5*sqrt3, 45}) || !corners[5].EqualApprox(Vec{100, 40}) {
+		t.Fatalf("pointy-top corners: %v", corners)
+	}
+}
+
+func TestHexRound(t *testing.T) {
+	tests := []struct {
+		frac Vec
+		want Hex
+	}{
+		{Vec{0.1, 0.1}, Hex{0, 0}},
+		{Vec{0.9, -0.1}, Hex{1, 0}},
+		{Vec{0.4, 0.4}, Hex{0, 1}},
+		{Vec{-1.6, 0.7}, Hex{-2, 1}},
+	}
+	for _, test := range tests {
+		if have := HexRound(test.frac); have != test.want {
+			t.Fatalf("HexRound(%v): have %v, want %v", test.frac, have, test.want)
+		}
+	}
+}
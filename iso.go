@@ -0,0 +1,119 @@
+package gmath
+
+import (
+	"math"
+)
+
+// IsoProjection maps the tile grid onto the screen as a diamond (isometric-like) grid.
+//
+// There are three coordinate spaces involved:
+//   - tile space: integer tile coordinates, an [Ivec]
+//   - world space: the grid plane measured in tiles, a [Vec];
+//     tile {X, Y} covers the [X, X+1) x [Y, Y+1) area
+//   - screen space: the projected coordinates, a [Vec]
+//
+// The world X axis goes to the bottom-right on the screen
+// and the world Y axis goes to the bottom-left.
+//
+// The tile and world spaces don't depend on the projection,
+// use [IsoWorldToTile] and [IsoTileToWorld] to convert between them.
+//
+// Use [MakeIsometric], [MakeTrueIsometric] or [MakeDimetric] to create
+// a projection for the common cases.
+type IsoProjection struct {
+	// TileSize is a size of the tile diamond on the screen.
+	// For the standard 2:1 isometric projection, it could be {64, 32}.
+	TileSize Vec
+
+	// Origin is a screen position of the world origin,
+	// which is the top corner of the {0, 0} tile diamond.
+	Origin Vec
+}
+
+// MakeIsometric creates a standard 2:1 isometric projection
+// (the tile height is half of its width).
+// This is the most common "isometric" projection in the pixel art games.
+func MakeIsometric(tileWidth float64, origin Vec) IsoProjection {
+	return IsoProjection{
+		TileSize: Vec{X: tileWidth, Y: tileWidth * 0.5},
+		Origin:   origin,
+	}
+}
+
+// MakeTrueIsometric creates a true isometric projection:
+// the diamond edges have a 30 degrees slope.
+func MakeTrueIsometric(tileWidth float64, origin Vec) IsoProjection {
+	return MakeDimetric(tileWidth, tileWidth*math.Tan(math.Pi/6), origin)
+}
+
+// MakeDimetric creates a projection with an arbitrary tile diamond size.
+//
+// The diamond edges have the atan(tileHeight/tileWidth) slope
+// relative to the horizontal axis: the 1:1 ratio gives 45 degrees,
+// the 2:1 ratio gives the standard [MakeIsometric] projection.
+func MakeDimetric(tileWidth, tileHeight float64, origin Vec) IsoProjection {
+	return IsoProjection{
+		TileSize: Vec{X: tileWidth, Y: tileHeight},
+		Origin:   origin,
+	}
+}
+
+// WorldToScreen projects the world point onto the screen.
+func (p IsoProjection) WorldToScreen(pos Vec) Vec {
+	return Vec{
+		X: p.Origin.X + (pos.X-pos.Y)*p.TileSize.X*0.5,
+		Y: p.Origin.Y + (pos.X+pos.Y)*p.TileSize.Y*0.5,
+	}
+}
+
+// ScreenToWorld converts the screen point back into the world point.
+func (p IsoProjection) ScreenToWorld(pos Vec) Vec {
+	dx := (pos.X - p.Origin.X) / p.TileSize.X
+	dy := (pos.Y - p.Origin.Y) / p.TileSize.Y
+	return Vec{
+		X: dy + dx,
+		Y: dy - dx,
+	}
+}
+
+// IsoWorldToTile returns a tile that contains the world point.
+func IsoWorldToTile(pos Vec) Ivec[int] {
+	return Ivec[int]{
+		X: int(math.Floor(pos.X)),
+		Y: int(math.Floor(pos.Y)),
+	}
+}
+
+// IsoTileToWorld returns the tile origin world point.
+// To get the tile center, add {0.5, 0.5} to it.
+func IsoTileToWorld(tile Ivec[int]) Vec {
+	return Vec{X: float64(tile.X), Y: float64(tile.Y)}
+}
+
+// ScreenToTile returns a tile that is displayed at the given screen point.
+//
+// The picking respects the diamond shape of the tile:
+// the points near the bounding rect corners belong to the neighbor tiles.
+func (p IsoProjection) ScreenToTile(pos Vec) Ivec[int] {
+	return IsoWorldToTile(p.ScreenToWorld(pos))
+}
+
+// TileToScreen returns a screen position of the tile diamond top corner.
+func (p IsoProjection) TileToScreen(tile Ivec[int]) Vec {
+	return p.WorldToScreen(IsoTileToWorld(tile))
+}
+
+// TileScreenCenter returns a screen position of the tile diamond center.
+func (p IsoProjection) TileScreenCenter(tile Ivec[int]) Vec {
+	top := p.TileToScreen(tile)
+	return Vec{X: top.X, Y: top.Y + p.TileSize.Y*0.5}
+}
+
+// TileScreenRect returns a screen bounding rect of the tile diamond.
+func (p IsoProjection) TileScreenRect(tile Ivec[int]) Rect {
+	top := p.TileToScreen(tile)
+	return Rect{
+		Min: Vec{X: top.X - p.TileSize.X*0.5, Y: top.Y},
+		Max: Vec{X: top.X + p.TileSize.X*0.5, Y: top.Y + p.TileSize.Y},
+	}
+}
@@ -0,0 +1,94 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIsoProjectionTileSize(t *testing.T) {
+	tests := []struct {
+		proj IsoProjection
+		want Vec
+	}{
+		{MakeIsometric(64, Vec{}), Vec{64, 32}},
+		{MakeDimetric(64, 32, Vec{}), Vec{64, 32}},
+		{MakeDimetric(64, 40, Vec{}), Vec{64, 40}},
+		{MakeTrueIsometric(60, Vec{}), Vec{60, 60 / math.Sqrt(3)}},
+	}
+	for _, test := range tests {
+		if !test.proj.TileSize.EqualApprox(test.want) {
+			t.Fatalf("TileSize:\nhave: %v\nwant: %v", test.proj.TileSize, test.want)
+		}
+	}
+}
+
+func TestIsoProjectionConversion(t *testing.T) {
+	proj := MakeIsometric(64, Vec{320, 16})
+
+	tests := []struct {
+		world  Vec
+		screen Vec
+	}{
+		{Vec{0, 0}, Vec{320, 16}},
+		{Vec{1, 0}, Vec{352, 32}},
+		{Vec{0, 1}, Vec{288, 32}},
+		{Vec{1, 1}, Vec{320, 48}},
+		{Vec{0.5, 0.5}, Vec{320, 32}},
+		{Vec{-2, 3}, Vec{160, 32}},
+	}
+	for _, test := range tests {
+		if have := proj.WorldToScreen(test.world); !have.EqualApprox(test.screen) {
+			t.Fatalf("WorldToScreen(%v):\nhave: %v\nwant: %v", test.world, have, test.screen)
+		}
+		if have := proj.ScreenToWorld(test.screen); !have.EqualApprox(test.world) {
+			t.Fatalf("ScreenToWorld(%v):\nhave: %v\nwant: %v", test.screen, have, test.world)
+		}
+	}
+
+	tile := Ivec[int]{X: 2, Y: 1}
+	if have := IsoTileToWorld(tile); have != (Vec{2, 1}) {
+		t.Fatalf("IsoTileToWorld(%v): %v", tile, have)
+	}
+	if have := IsoWorldToTile(Vec{2.5, 1.99}); have != tile {
+		t.Fatalf("IsoWorldToTile(%v): %v", Vec{2.5, 1.99}, have)
+	}
+	if have := IsoWorldToTile(Vec{-0.5, 0}); have != (Ivec[int]{X: -1, Y: 0}) {
+		t.Fatalf("IsoWorldToTile(%v): %v", Vec{-0.5, 0}, have)
+	}
+	if have := proj.TileToScreen(tile); have != (Vec{352, 64}) {
+		t.Fatalf("TileToScreen(%v): %v", tile, have)
+	}
+	if have := proj.TileScreenCenter(tile); have != (Vec{352, 80}) {
+		t.Fatalf("TileScreenCenter(%v): %v", tile, have)
+	}
+	want := Rect{Min: Vec{320, 64}, Max: Vec{384, 96}}
+	if have := proj.TileScreenRect(tile); have != want {
+		t.Fatalf("TileScreenRect(%v):\nhave: %v\nwant: %v", tile, have, want)
+	}
+}
+
+func TestIsoProjectionPicking(t *testing.T) {
+	proj := MakeIsometric(64, Vec{320, 16})
+	tile := Ivec[int]{X: 2, Y: 1}
+	r := proj.TileScreenRect(tile)
+
+	tests := []struct {
+		screen Vec
+		want   Ivec[int]
+	}{
+		{proj.TileScreenCenter(tile), tile},
+		{r.Min.Add(Vec{33, 1}), tile},
+		{r.Min.Add(Vec{1, 16}), tile},
+		// The bounding rect corners belong to the neighbors.
+		{r.Min.Add(Vec{1, 1}), Ivec[int]{X: 1, Y: 1}},
+		{Vec{r.Max.X - 1, r.Min.Y + 1}, Ivec[int]{X: 2, Y: 0}},
+		{Vec{r.Min.X + 1, r.Max.Y - 1}, Ivec[int]{X: 2, Y: 2}},
+		{r.Max.Sub(Vec{1, 1}), Ivec[int]{X: 3, Y: 1}},
+		{Vec{300, 0}, Ivec[int]{X: -1, Y: -1}},
+	}
+	for _, test := range tests {
+		if have := proj.ScreenToTile(test.screen); have != test.want {
+			t.Fatalf("ScreenToTile(%v): have %v, want %v", test.screen, have, test.want)
+		}
+	}
+}
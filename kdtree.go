@@ -0,0 +1,264 @@
+package gmath
+
+// KDPoint is a [KDTree] element: a point with an associated value.
+type KDPoint[T any] struct {
+	Pos   Vec
+	Value T
+}
+
+// KDTree is a static 2D k-d tree for the nearest neighbor queries.
+//
+// The tree can't be modified after it's built;
+// use [KDTree.Rebuild] to re-create it from a new set of points.
+// Rebuilding reuses the allocated memory.
+//
+// All queries accept an optional filter function;
+// the points it rejects are ignored. A nil filter accepts every point.
+type KDTree[T any] struct {
+	// The points are stored in an implicit balanced tree layout:
+	// the median of a range is its root, the left and right halves are its subtrees.
+	// The even depth levels split by X, the odd ones split by Y.
+	points []KDPoint[T]
+}
+
+// NewKDTree builds a tree from the points and their values.
+// The values slice can be nil; otherwise it should have the same length as points.
+//
+// The input slices are not modified or retained.
+func NewKDTree[T any](points []Vec, values []T) *KDTree[T] {
+	t := &KDTree[T]{}
+	t.Rebuild(points, values)
+	return t
+}
+
+// Rebuild re-creates the tree from the new points and values.
+// See [NewKDTree].
+func (t *KDTree[T]) Rebuild(points []Vec, values []T) {
+	if values != nil && len(values) != len(points) {
+		panic("points and values lengths mismatch")
+	}
+	if cap(t.points) < len(points) {
+		t.points = make([]KDPoint[T], len(points))
+	}
+	t.points = t.points[:len(points)]
+	var zero T
+	for i, p := range points {
+		t.points[i].Pos = p
+		if values != nil {
+			t.points[i].Value = values[i]
+		} else {
+			t.points[i].Value = zero
+		}
+	}
+	t.build(0, len(t.points), 0)
+}
+
+// Len returns the number of points inside the tree.
+func (t *KDTree[T]) Len() int { return len(t.points) }
+
+// Nearest finds the point that is the closest to p.
+// It returns false if there are no points that pass the filter.
+func (t *KDTree[T]) Nearest(p Vec, filter func(KDPoint[T]) bool) (KDPoint[T], bool) {
+	best := -1
+	bestDist := 0.0
+	t.nearest(0, len(t.points), 0, p, filter, &best, &bestDist)
+	if best == -1 {
+		return KDPoint[T]{}, false
+	}
+	return t.points[best], true
+}
+
+// KNearest appends up to k points that are the closest to p to dst.
+// The appended points are sorted by their distance to p, the closest go first.
+func (t *KDTree[T]) KNearest(dst []KDPoint[T], p Vec, k int, filter func(KDPoint[T]) bool) []KDPoint[T] {
+	if k <= 0 {
+		return dst
+	}
+	// The tail of dst is used as a bounded max-heap.
+	h := kdHeap[T]{points: dst[len(dst):], p: p}
+	t.kNearest(0, len(t.points), 0, &h, k, filter)
+	// Sort the heap in place: pop the farthest point into the tail until it's empty.
+	result := h.points
+	for n := len(result) - 1; n > 0; n-- {
+		result[0], result[n] = result[n], result[0]
+		h.points = result[:n]
+		h.down(0)
+	}
+	return append(dst, result...)
+}
+
+// QueryRadius appends all points that are within r distance from p to dst.
+// The points are appended in no particular order.
+func (t *KDTree[T]) QueryRadius(dst []KDPoint[T], p Vec, r float64, filter func(KDPoint[T]) bool) []KDPoint[T] {
+	return t.queryRadius(dst, 0, len(t.points), 0, p, r*r, filter)
+}
+
+func (t *KDTree[T]) build(lo, hi, depth int) {
+	if hi-lo <= 1 {
+		return
+	}
+	mid := (lo + hi) / 2
+	kdSelect(t.points, lo, hi-1, mid, depth%2)
+	t.build(lo, mid, depth+1)
+	t.build(mid+1, hi, depth+1)
+}
+
+func (t *KDTree[T]) nearest(lo, hi, depth int, p Vec, filter func(KDPoint[T]) bool, best *int, bestDist *float64) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	pt := &t.points[mid]
+	if d := pt.Pos.DistanceSquaredTo(p); (*best == -1 || d < *bestDist) && (filter == nil || filter(*pt)) {
+		*best = mid
+		*bestDist = d
+	}
+
+	diff := kdAxisValue(p, depth) - kdAxisValue(pt.Pos, depth)
+	nearLo, nearHi, farLo, farHi := lo, mid, mid+1, hi
+	if diff > 0 {
+		nearLo, nearHi, farLo, farHi = farLo, farHi, nearLo, nearHi
+	}
+	t.nearest(nearLo, nearHi, depth+1, p, filter, best, bestDist)
+	if *best == -1 || diff*diff < *bestDist {
+		t.nearest(farLo, farHi, depth+1, p, filter, best, bestDist)
+	}
+}
+
+func (t *KDTree[T]) kNearest(lo, hi, depth int, h *kdHeap[T], k int, filter func(KDPoint[T]) bool) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	pt := &t.points[mid]
+	if filter == nil || filter(*pt) {
+		d := pt.Pos.DistanceSquaredTo(h.p)
+		if len(h.points) < k {
+			h.push(*pt)
+		} else if d < h.points[0].Pos.DistanceSquaredTo(h.p) {
+			h.points[0] = *pt
+			h.down(0)
+		}
+	}
+
+	diff := kdAxisValue(h.p, depth) - kdAxisValue(pt.Pos, depth)
+	nearLo, nearHi, farLo, farHi := lo, mid, mid+1, hi
+	if diff > 0 {
+		nearLo, nearHi, farLo, farHi = farLo, farHi, nearLo, nearHi
+	}
+	t.kNearest(nearLo, nearHi, depth+1, h, k, filter)
+	if len(h.points) < k || diff*diff < h.points[0].Pos.DistanceSquaredTo(h.p) {
+		t.kNearest(farLo, farHi, depth+1, h, k, filter)
+	}
+}
+
+func (t *KDTree[T]) queryRadius(dst []KDPoint[T], lo, hi, depth int, p Vec, r2 float64, filter func(KDPoint[T]) bool) []KDPoint[T] {
+	if lo >= hi {
+		return dst
+	}
+	mid := (lo + hi) / 2
+	pt := &t.points[mid]
+	if pt.Pos.DistanceSquaredTo(p) <= r2 && (filter == nil || filter(*pt)) {
+		dst = append(dst, *pt)
+	}
+
+	diff := kdAxisValue(p, depth) - kdAxisValue(pt.Pos, depth)
+	if diff <= 0 || diff*diff <= r2 {
+		dst = t.queryRadius(dst, lo, mid, depth+1, p, r2, filter)
+	}
+	if diff >= 0 || diff*diff <= r2 {
+		dst = t.queryRadius(dst, mid+1, hi, depth+1, p, r2, filter)
+	}
+	return dst
+}
+
+func kdAxisValue(p Vec, depth int) float64 {
+	if depth%2 == 0 {
+		return p.X
+	}
+	return p.Y
+}
+
+// kdSelect partially sorts points[lo:hi+1] so the k-th element
+// is at its sorted position by the specified axis; the smaller elements
+// go before it and the bigger elements go after it.
+func kdSelect[T any](points []KDPoint[T], lo, hi, k, axis int) {
+	for lo < hi {
+		// Median of three makes the sorted input a good case.
+		mid := (lo + hi) / 2
+		if kdAxisValue(points[mid].Pos, axis) < kdAxisValue(points[lo].Pos, axis) {
+			points[mid], points[lo] = points[lo], points[mid]
+		}
+		if kdAxisValue(points[hi].Pos, axis) < kdAxisValue(points[lo].Pos, axis) {
+			points[hi], points[lo] = points[lo], points[hi]
+		}
+		if kdAxisValue(points[hi].Pos, axis) < kdAxisValue(points[mid].Pos, axis) {
+			points[hi], points[mid] = points[mid], points[hi]
+		}
+		pivot := kdAxisValue(points[mid].Pos, axis)
+
+		i, j := lo, hi
+		for i <= j {
+			for kdAxisValue(points[i].Pos, axis) < pivot {
+				i++
+			}
+			for kdAxisValue(points[j].Pos, axis) > pivot {
+				j--
+			}
+			if i <= j {
+				points[i], points[j] = points[j], points[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case k <= j:
+			hi = j
+		case k >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// kdHeap is a max-heap of points by their distance to p.
+type kdHeap[T any] struct {
+	points []KDPoint[T]
+	p      Vec
+}
+
+func (h *kdHeap[T]) less(i, j int) bool {
+	return h.points[i].Pos.DistanceSquaredTo(h.p) > h.points[j].Pos.DistanceSquaredTo(h.p)
+}
+
+func (h *kdHeap[T]) push(pt KDPoint[T]) {
+	h.points = append(h.points, pt)
+	i := len(h.points) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			break
+		}
+		h.points[i], h.points[parent] = h.points[parent], h.points[i]
+		i = parent
+	}
+}
+
+func (h *kdHeap[T]) down(i int) {
+	n := len(h.points)
+	for {
+		largest := i
+		if l := 2*i + 1; l < n && h.less(l, largest) {
+			largest = l
+		}
+		if r := 2*i + 2; r < n && h.less(r, largest) {
+			largest = r
+		}
+		if largest == i {
+			return
+		}
+		h.points[i], h.points[largest] = h.points[largest], h.points[i]
+		i = largest
+	}
+}
@@ -0,0 +1,114 @@
+package gmath
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestKDTreeRandomized(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := NewKDTree[int](nil, nil)
+
+	for round := 0; round < 50; round++ {
+		n := rng.Intn(200)
+		points := make([]Vec, n)
+		values := make([]int, n)
+		for i := range points {
+			// Use a coarse grid to get some duplicated coordinates.
+			points[i] = Vec{float64(rng.Intn(50)), float64(rng.Intn(50))}
+			values[i] = i
+		}
+		tree.Rebuild(points, values)
+		if tree.Len() != n {
+			t.Fatalf("round %d: Len()=%d, want %d", round, tree.Len(), n)
+		}
+
+		p := Vec{rng.Float64() * 50, rng.Float64() * 50}
+		var filter func(KDPoint[int]) bool
+		if round%2 == 0 {
+			filter = func(pt KDPoint[int]) bool { return pt.Value%3 != 0 }
+		}
+		accepted := func(i int) bool {
+			return filter == nil || filter(KDPoint[int]{Pos: points[i], Value: i})
+		}
+
+		var sorted []int
+		for i := range points {
+			if accepted(i) {
+				sorted = append(sorted, i)
+			}
+		}
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return points[sorted[i]].DistanceSquaredTo(p) < points[sorted[j]].DistanceSquaredTo(p)
+		})
+
+		nearest, ok := tree.Nearest(p, filter)
+		if ok != (len(sorted) != 0) {
+			t.Fatalf("round %d: Nearest returned %v", round, ok)
+		}
+		if ok {
+			if !accepted(nearest.Value) || points[nearest.Value] != nearest.Pos {
+				t.Fatalf("round %d: Nearest returned invalid point %v", round, nearest)
+			}
+			if nearest.Pos.DistanceSquaredTo(p) != points[sorted[0]].DistanceSquaredTo(p) {
+				t.Fatalf("round %d: Nearest(%v):\nhave: %v\nwant: %v", round, p, nearest.Pos, points[sorted[0]])
+			}
+		}
+
+		k := 1 + rng.Intn(10)
+		knn := tree.KNearest([]KDPoint[int]{{Value: -1}}, p, k, filter)
+		if knn[0].Value != -1 {
+			t.Fatalf("round %d: KNearest overwrote dst", round)
+		}
+		knn = knn[1:]
+		wantLen := k
+		if len(sorted) < k {
+			wantLen = len(sorted)
+		}
+		if len(knn) != wantLen {
+			t.Fatalf("round %d: KNearest returned %d points, want %d", round, len(knn), wantLen)
+		}
+		for i, pt := range knn {
+			if !accepted(pt.Value) {
+				t.Fatalf("round %d: KNearest returned a filtered point %v", round, pt)
+			}
+			if pt.Pos.DistanceSquaredTo(p) != points[sorted[i]].DistanceSquaredTo(p) {
+				t.Fatalf("round %d: KNearest[%d]:\nhave: %v\nwant: %v", round, i, pt.Pos, points[sorted[i]])
+			}
+		}
+
+		r := rng.Float64() * 20
+		inRadius := tree.QueryRadius(nil, p, r, filter)
+		want := 0
+		for _, i := range sorted {
+			if points[i].DistanceTo(p) <= r {
+				want++
+			}
+		}
+		if len(inRadius) != want {
+			t.Fatalf("round %d: QueryRadius found %d points, want %d", round, len(inRadius), want)
+		}
+	}
+}
+
+func TestKDTreeNoAllocs(t *testing.T) {
+	points := make([]Vec, 100)
+	for i := range points {
+		points[i] = Vec{float64(i % 10), float64(i / 10)}
+	}
+	tree := NewKDTree[int](points, nil)
+	buf := make([]KDPoint[int], 0, 16)
+	allocs := testing.AllocsPerRun(100, func() {
+		tree.Rebuild(points, nil)
+		buf = tree.KNearest(buf[:0], Vec{5, 5}, 5, nil)
+		buf = tree.QueryRadius(buf[:0], Vec{5, 5}, 1.5, nil)
+		tree.Nearest(Vec{3.3, 3.3}, nil)
+	})
+	if allocs != 0 {
+		t.Fatalf("tree operations allocate: %v", allocs)
+	}
+	if len(buf) != 9 {
+		t.Fatalf("QueryRadius found %d points, want 9", len(buf))
+	}
+}
@@ -0,0 +1,240 @@
+package gmath
+
+import (
+	"math"
+)
+
+// Mat2 is a 2x2 matrix stored in the row-major order: m[row][col].
+//
+// Note that a zero value is a zero matrix, use [Mat2Identity] to get an identity matrix.
+type Mat2 [2][2]float64
+
+// Mat3 is a 3x3 matrix stored in the row-major order: m[row][col].
+//
+// Note that a zero value is a zero matrix, use [Mat3Identity] to get an identity matrix.
+type Mat3 [3][3]float64
+
+// Vec3 is a 3D vector that is used as a [Mat3] operand.
+type Vec3 struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+func Mat2Identity() Mat2 {
+	return Mat2{
+		{1, 0},
+		{0, 1},
+	}
+}
+
+// Mul returns the m*other matrix product.
+func (m Mat2) Mul(other Mat2) Mat2 {
+	var result Mat2
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			result[i][j] = m[i][0]*other[0][j] + m[i][1]*other[1][j]
+		}
+	}
+	return result
+}
+
+func (m Mat2) Transpose() Mat2 {
+	return Mat2{
+		{m[0][0], m[1][0]},
+		{m[0][1], m[1][1]},
+	}
+}
+
+func (m Mat2) Determinant() float64 {
+	return m[0][0]*m[1][1] - m[0][1]*m[1][0]
+}
+
+// Inverse returns the inverse matrix.
+// It returns false if the matrix is singular.
+//
+// A matrix is treated as singular if its determinant is close to zero
+// relative to the elements magnitude, so the nearly singular matrices
+// like {{0.1, 0.2}, {0.3, 0.6}} are rejected too.
+func (m Mat2) Inverse() (Mat2, bool) {
+	det, ok := det2(m[0][0], m[0][1], m[1][0], m[1][1])
+	if !ok {
+		return Mat2{}, false
+	}
+	inv := 1 / det
+	return Mat2{
+		{m[1][1] * inv, -m[0][1] * inv},
+		{-m[1][0] * inv, m[0][0] * inv},
+	}, true
+}
+
+// Apply returns the m*v product, where v is a column vector.
+func (m Mat2) Apply(v Vec) Vec {
+	return Vec{
+		X: m[0][0]*v.X + m[0][1]*v.Y,
+		Y: m[1][0]*v.X + m[1][1]*v.Y,
+	}
+}
+
+// Solve finds x for the m*x=b linear system.
+// It returns false if the system has no unique solution (the matrix is singular).
+// The singularity test is the same as in [Mat2.Inverse].
+func (m Mat2) Solve(b Vec) (Vec, bool) {
+	det, ok := det2(m[0][0], m[0][1], m[1][0], m[1][1])
+	if !ok {
+		return Vec{}, false
+	}
+	// Cramer's rule.
+	x := Vec{
+		X: (b.X*m[1][1] - m[0][1]*b.Y) / det,
+		Y: (m[0][0]*b.Y - b.X*m[1][0]) / det,
+	}
+	return x, true
+}
+
+// SymmetricEigenvalues returns the eigenvalues of a symmetric matrix.
+// The result is ordered: max >= min.
+//
+// Only m[0][1] is used as an off-diagonal element, m[1][0] is ignored.
+func (m Mat2) SymmetricEigenvalues() (max, min float64) {
+	mean := (m[0][0] + m[1][1]) * 0.5
+	r := math.Hypot((m[0][0]-m[1][1])*0.5, m[0][1])
+	return mean + r, mean - r
+}
+
+func Mat3Identity() Mat3 {
+	return Mat3{
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+	}
+}
+
+// Mat3FromAffine2D converts an affine matrix into its 3x3 homogeneous form.
+func Mat3FromAffine2D(m Affine2D) Mat3 {
+	a, b, c, d, tx, ty := m.Elements()
+	return Mat3{
+		{a, b, tx},
+		{c, d, ty},
+		{0, 0, 1},
+	}
+}
+
+// Mul returns the m*other matrix product.
+func (m Mat3) Mul(other Mat3) Mat3 {
+	var result Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			result[i][j] = m[i][0]*other[0][j] + m[i][1]*other[1][j] + m[i][2]*other[2][j]
+		}
+	}
+	return result
+}
+
+func (m Mat3) Transpose() Mat3 {
+	var result Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			result[i][j] = m[j][i]
+		}
+	}
+	return result
+}
+
+func (m Mat3) Determinant() float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// checkedDeterminant is like Determinant, but it also reports
+// whether the matrix is not singular or nearly singular.
+// The tolerance is relative to the magnitude of the cofactor expansion terms.
+func (m Mat3) checkedDeterminant() (float64, bool) {
+	var det, scale float64
+	sign := 1.0
+	for col := 0; col < 3; col++ {
+		// The minor columns that remain after col is removed.
+		c1 := (col + 1) % 3
+		c2 := (col + 2) % 3
+		if c1 > c2 {
+			c1, c2 = c2, c1
+		}
+		p := m[1][c1] * m[2][c2]
+		q := m[1][c2] * m[2][c1]
+		det += sign * m[0][col] * (p - q)
+		scale += math.Abs(m[0][col]) * (math.Abs(p) + math.Abs(q))
+		sign = -sign
+	}
+	return det, math.Abs(det) > Epsilon*scale
+}
+
+// Inverse returns the inverse matrix.
+// It returns false if the matrix is singular (see [Mat2.Inverse]).
+func (m Mat3) Inverse() (Mat3, bool) {
+	det, ok := m.checkedDeterminant()
+	if !ok {
+		return Mat3{}, false
+	}
+	inv := 1 / det
+	// The inverse is a transposed cofactor matrix divided by the determinant.
+	return Mat3{
+		{
+			(m[1][1]*m[2][2] - m[1][2]*m[2][1]) * inv,
+			(m[0][2]*m[2][1] - m[0][1]*m[2][2]) * inv,
+			(m[0][1]*m[1][2] - m[0][2]*m[1][1]) * inv,
+		},
+		{
+			(m[1][2]*m[2][0] - m[1][0]*m[2][2]) * inv,
+			(m[0][0]*m[2][2] - m[0][2]*m[2][0]) * inv,
+			(m[0][2]*m[1][0] - m[0][0]*m[1][2]) * inv,
+		},
+		{
+			(m[1][0]*m[2][1] - m[1][1]*m[2][0]) * inv,
+			(m[0][1]*m[2][0] - m[0][0]*m[2][1]) * inv,
+			(m[0][0]*m[1][1] - m[0][1]*m[1][0]) * inv,
+		},
+	}, true
+}
+
+// Apply returns the m*v product, where v is a column vector.
+func (m Mat3) Apply(v Vec3) Vec3 {
+	return Vec3{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z,
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z,
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z,
+	}
+}
+
+// ApplyVec transforms the 2D point using the homogeneous coordinates:
+// the point is extended to {X, Y, 1} and the result is divided by its Z.
+//
+// For the affine matrices (see [Mat3FromAffine2D]) the Z is always 1.
+// If the resulting Z is 0, the point is at infinity and its X and Y are returned as is.
+func (m Mat3) ApplyVec(v Vec) Vec {
+	p := m.Apply(Vec3{X: v.X, Y: v.Y, Z: 1})
+	if p.Z == 0 || p.Z == 1 {
+		return Vec{X: p.X, Y: p.Y}
+	}
+	return Vec{X: p.X / p.Z, Y: p.Y / p.Z}
+}
+
+// Solve finds x for the m*x=b linear system.
+// It returns false if the system has no unique solution (the matrix is singular).
+// The singularity test is the same as in [Mat3.Inverse].
+func (m Mat3) Solve(b Vec3) (Vec3, bool) {
+	det, ok := m.checkedDeterminant()
+	if !ok {
+		return Vec3{}, false
+	}
+	// Cramer's rule: every column is replaced by b in turn.
+	var result [3]float64
+	for col := 0; col < 3; col++ {
+		mi := m
+		mi[0][col] = b.X
+		mi[1][col] = b.Y
+		mi[2][col] = b.Z
+		result[col] = mi.Determinant() / det
+	}
+	return Vec3{X: result[0], Y: result[1], Z: result[2]}, true
+}
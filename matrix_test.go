@@ -0,0 +1,219 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func mat2EqualApprox(a, b Mat2) bool {
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			if !EqualApprox(a[i][j], b[i][j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func mat3EqualApprox(a, b Mat3) bool {
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if !EqualApprox(a[i][j], b[i][j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestMat2(t *testing.T) {
+	m := Mat2{
+		{2, 1},
+		{5, 3},
+	}
+	if have := m.Determinant(); have != 1 {
+		t.Fatalf("Determinant(): %v", have)
+	}
+	want := Mat2{
+		{2, 5},
+		{1, 3},
+	}
+	if have := m.Transpose(); have != want {
+		t.Fatalf("Transpose(): %v", have)
+	}
+	want = Mat2{
+		{9, 5},
+		{25, 14},
+	}
+	if have := m.Mul(m); have != want {
+		t.Fatalf("Mul(): %v", have)
+	}
+
+	inv, ok := m.Inverse()
+	if !ok {
+		t.Fatal("matrix is not invertible")
+	}
+	if have := m.Mul(inv); !mat2EqualApprox(have, Mat2Identity()) {
+		t.Fatalf("m*inverse: %v", have)
+	}
+
+	x, ok := m.Solve(Vec{4, 11})
+	if !ok || !x.EqualApprox(Vec{1, 2}) {
+		t.Fatalf("Solve(): %v %v", x, ok)
+	}
+	if have := m.Apply(x); !have.EqualApprox(Vec{4, 11}) {
+		t.Fatalf("Apply(): %v", have)
+	}
+
+	singular := Mat2{
+		{1, 2},
+		{2, 4},
+	}
+	if _, ok := singular.Inverse(); ok {
+		t.Fatal("singular matrix is invertible")
+	}
+	if _, ok := singular.Solve(Vec{1, 1}); ok {
+		t.Fatal("singular system is solved")
+	}
+
+	nearlySingular := []Mat2{
+		{{0.1, 0.2}, {0.3, 0.6}},
+		{{1, 1}, {1, 1 + 1e-12}},
+		{{0, 0}, {0, 0}},
+	}
+	for _, m := range nearlySingular {
+		if _, ok := m.Inverse(); ok {
+			t.Fatalf("nearly singular matrix %v is invertible", m)
+		}
+		if _, ok := m.Solve(Vec{1, 1}); ok {
+			t.Fatalf("nearly singular system %v is solved", m)
+		}
+	}
+	// A small scale is not a singularity.
+	if _, ok := (Mat2{{1e-12, 0}, {0, 1e-12}}).Inverse(); !ok {
+		t.Fatal("scaled down matrix is not invertible")
+	}
+}
+
+func TestMat2SymmetricEigenvalues(t *testing.T) {
+	tests := []struct {
+		m        Mat2
+		max, min float64
+	}{
+		{Mat2Identity(), 1, 1},
+		{Mat2{{2, 0}, {0, 5}}, 5, 2},
+		{Mat2{{2, 1}, {1, 2}}, 3, 1},
+		{Mat2{{4, -2}, {-2, 1}}, 5, 0},
+	}
+	for _, test := range tests {
+		max, min := test.m.SymmetricEigenvalues()
+		if !EqualApprox(max, test.max) || !EqualApprox(min, test.min) {
+			t.Fatalf("SymmetricEigenvalues(%v): have %v %v, want %v %v", test.m, max, min, test.max, test.min)
+		}
+		// The eigenvalues of a 2x2 matrix sum up to its trace
+		// and their product is the determinant.
+		if !EqualApprox(max*min, test.m.Determinant()) {
+			t.Fatalf("SymmetricEigenvalues(%v): product mismatch", test.m)
+		}
+	}
+}
+
+func TestMat3(t *testing.T) {
+	m := Mat3{
+		{2, 0, 1},
+		{1, 3, 2},
+		{1, 1, 2},
+	}
+	if have := m.Determinant(); !EqualApprox(have, 6) {
+		t.Fatalf("Determinant(): %v", have)
+	}
+	if have := m.Transpose().Transpose(); have != m {
+		t.Fatalf("Transpose() round trip: %v", have)
+	}
+	if have := m.Transpose()[0][1]; have != 1 {
+		t.Fatalf("Transpose()[0][1]: %v", have)
+	}
+
+	inv, ok := m.Inverse()
+	if !ok {
+		t.Fatal("matrix is not invertible")
+	}
+	if have := m.Mul(inv); !mat3EqualApprox(have, Mat3Identity()) {
+		t.Fatalf("m*inverse: %v", have)
+	}
+	if have := inv.Mul(m); !mat3EqualApprox(have, Mat3Identity()) {
+		t.Fatalf("inverse*m: %v", have)
+	}
+
+	b := Vec3{X: 5, Y: 13, Z: 7}
+	x, ok := m.Solve(b)
+	want := Vec3{X: 2, Y: 3, Z: 1}
+	if !ok || !EqualApprox(x.X, want.X) || !EqualApprox(x.Y, want.Y) || !EqualApprox(x.Z, want.Z) {
+		t.Fatalf("Solve(): %v %v", x, ok)
+	}
+	if have := m.Apply(want); have != b {
+		t.Fatalf("Apply(): %v", have)
+	}
+
+	singular := Mat3{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	}
+	if _, ok := singular.Inverse(); ok {
+		t.Fatal("singular matrix is invertible")
+	}
+	if _, ok := singular.Solve(b); ok {
+		t.Fatal("singular system is solved")
+	}
+
+	nearlySingular := []Mat3{
+		{{0.1, 0.2, 0}, {0.3, 0.6, 0}, {0, 0, 1}},
+		{{0.1, 0.2, 0.3}, {0.4, 0.5, 0.6}, {0.7, 0.8, 0.9}},
+		{{1, 1, 1}, {1, 1 + 1e-12, 1}, {0, 0, 1}},
+		Mat3FromAffine2D(Affine2DFromElements(0.1, 0.2, 0.3, 0.6, 5, 7)),
+	}
+	for _, m := range nearlySingular {
+		if _, ok := m.Inverse(); ok {
+			t.Fatalf("nearly singular matrix %v is invertible", m)
+		}
+		if _, ok := m.Solve(b); ok {
+			t.Fatalf("nearly singular system %v is solved", m)
+		}
+	}
+	tiny := Mat3{{1e-12, 0, 0}, {0, 1e-12, 0}, {0, 0, 1e-12}}
+	if _, ok := tiny.Inverse(); !ok {
+		t.Fatal("scaled down matrix is not invertible")
+	}
+	if _, ok := m.checkedDeterminant(); !ok || !EqualApprox(m.Determinant(), 6) {
+		t.Fatal("checkedDeterminant rejects a regular matrix")
+	}
+}
+
+func TestMat3FromAffine2D(t *testing.T) {
+	var a Affine2D
+	a.Scale(Vec{2, 3})
+	a.Rotate(math.Pi / 3)
+	a.Translate(Vec{10, -4})
+	m := Mat3FromAffine2D(a)
+
+	if !EqualApprox(m.Determinant(), a.Determinant()) {
+		t.Fatalf("Determinant(): have %v, want %v", m.Determinant(), a.Determinant())
+	}
+	for _, p := range []Vec{{}, {1, 0}, {-3, 7}} {
+		if have, want := m.ApplyVec(p), a.Apply(p); !have.EqualApprox(want) {
+			t.Fatalf("ApplyVec(%v):\nhave: %v\nwant: %v", p, have, want)
+		}
+	}
+
+	// A projective matrix needs the division by Z.
+	projective := Mat3{
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 2},
+	}
+	if have := projective.ApplyVec(Vec{4, 6}); have != (Vec{2, 3}) {
+		t.Fatalf("projective ApplyVec(): %v", have)
+	}
+}
@@ -0,0 +1,151 @@
+package gmath
+
+import (
+	"math"
+)
+
+// OBB is an oriented bounding box: a rectangle that can be rotated.
+//
+// Unlike [Rect], it's defined by its center point, so
+// the rotation is always performed around the box center.
+type OBB struct {
+	Center Vec
+
+	// HalfExtents are the half-width (X) and half-height (Y) of the box.
+	HalfExtents Vec
+
+	Rotation Rad
+}
+
+// OBBFromRect converts an axis-aligned rectangle into an [OBB] with zero rotation.
+func OBBFromRect(r Rect) OBB {
+	return OBB{
+		Center:      r.Center(),
+		HalfExtents: r.Size().Mulf(0.5),
+	}
+}
+
+// Axes returns the box local X and Y axes in the world space.
+// Both vectors are normalized.
+func (b OBB) Axes() (x, y Vec) {
+	sin, cos := math.Sincos(float64(b.Rotation))
+	x = Vec{X: cos, Y: sin}
+	y = Vec{X: -sin, Y: cos}
+	return x, y
+}
+
+// Corners returns the box vertices in the clockwise order.
+// For a box without rotation, the first corner is the top-left one.
+func (b OBB) Corners() [4]Vec {
+	ax, ay := b.Axes()
+	x := ax.Mulf(b.HalfExtents.X)
+	y := ay.Mulf(b.HalfExtents.Y)
+	return [4]Vec{
+		b.Center.Sub(x).Sub(y),
+		b.Center.Add(x).Sub(y),
+		b.Center.Add(x).Add(y),
+		b.Center.Sub(x).Add(y),
+	}
+}
+
+// Bounds returns the smallest axis-aligned rectangle that contains the box.
+func (b OBB) Bounds() Rect {
+	ax, ay := b.Axes()
+	extents := Vec{
+		X: math.Abs(ax.X)*b.HalfExtents.X + math.Abs(ay.X)*b.HalfExtents.Y,
+		Y: math.Abs(ax.Y)*b.HalfExtents.X + math.Abs(ay.Y)*b.HalfExtents.Y,
+	}
+	return Rect{
+		Min: b.Center.Sub(extents),
+		Max: b.Center.Add(extents),
+	}
+}
+
+// ToLocal converts a world space point into the box local space.
+// In the local space, the box center is at the origin and it has no rotation.
+func (b OBB) ToLocal(p Vec) Vec {
+	ax, ay := b.Axes()
+	d := p.Sub(b.Center)
+	return Vec{X: d.Dot(ax), Y: d.Dot(ay)}
+}
+
+// ToWorld converts a box local space point into the world space.
+// It's an inverse of [OBB.ToLocal].
+func (b OBB) ToWorld(p Vec) Vec {
+	ax, ay := b.Axes()
+	return b.Center.Add(ax.Mulf(p.X)).Add(ay.Mulf(p.Y))
+}
+
+// Contains reports whether the point is inside the box.
+// The points on the box border are considered to be inside.
+func (b OBB) Contains(p Vec) bool {
+	local := b.ToLocal(p)
+	return math.Abs(local.X) <= b.HalfExtents.X && math.Abs(local.Y) <= b.HalfExtents.Y
+}
+
+// ClosestPoint returns the point of the box that is closest to p.
+// For a point that is inside the box, the point itself is returned.
+func (b OBB) ClosestPoint(p Vec) Vec {
+	local := b.ToLocal(p)
+	local.X = Clamp(local.X, -b.HalfExtents.X, b.HalfExtents.X)
+	local.Y = Clamp(local.Y, -b.HalfExtents.Y, b.HalfExtents.Y)
+	return b.ToWorld(local)
+}
+
+// Support returns the box corner that is the farthest in the given direction.
+// It implements the [Supporter] interface.
+func (b OBB) Support(dir Vec) Vec {
+	ax, ay := b.Axes()
+	p := b.Center
+	if dir.Dot(ax) > 0 {
+		p = p.Add(ax.Mulf(b.HalfExtents.X))
+	} else {
+		p = p.Sub(ax.Mulf(b.HalfExtents.X))
+	}
+	if dir.Dot(ay) > 0 {
+		p = p.Add(ay.Mulf(b.HalfExtents.Y))
+	} else {
+		p = p.Sub(ay.Mulf(b.HalfExtents.Y))
+	}
+	return p
+}
+
+// Translate moves the box by the given offset.
+func (b *OBB) Translate(offset Vec) {
+	b.Center = b.Center.Add(offset)
+}
+
+// IntersectsCircle reports whether the box and the circle overlap.
+// Like with [Rect.Intersects], the touching shapes do not intersect.
+func (b OBB) IntersectsCircle(c Circle) bool {
+	return b.ClosestPoint(c.Center).DistanceSquaredTo(c.Center) < c.Radius*c.Radius
+}
+
+// IntersectsRect reports whether the box and the rectangle overlap.
+// Like with [Rect.Intersects], the touching shapes do not intersect.
+func (b OBB) IntersectsRect(r Rect) bool {
+	if r.IsEmpty() {
+		return false
+	}
+	return b.IntersectsOBB(OBBFromRect(r))
+}
+
+// IntersectsOBB reports whether two boxes overlap.
+// Like with [Rect.Intersects], the touching shapes do not intersect.
+func (b OBB) IntersectsOBB(other OBB) bool {
+	ax1, ay1 := b.Axes()
+	ax2, ay2 := other.Axes()
+	d := other.Center.Sub(b.Center)
+
+	// Separating axis test: two convex shapes don't overlap
+	// if there is an axis where their projections are disjoint.
+	// For the boxes, only their edge normals need to be checked.
+	for _, axis := range [4]Vec{ax1, ay1, ax2, ay2} {
+		r1 := b.HalfExtents.X*math.Abs(ax1.Dot(axis)) + b.HalfExtents.Y*math.Abs(ay1.Dot(axis))
+		r2 := other.HalfExtents.X*math.Abs(ax2.Dot(axis)) + other.HalfExtents.Y*math.Abs(ay2.Dot(axis))
+		if math.Abs(d.Dot(axis)) >= r1+r2 {
+			return false
+		}
+	}
+	return true
+}
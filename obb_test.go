@@ -0,0 +1,128 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOBBCorners(t *testing.T) {
+	tests := []struct {
+		b    OBB
+		want [4]Vec
+	}{
+		{
+			OBB{Center: Vec{10, 10}, HalfExtents: Vec{2, 1}},
+			[4]Vec{{8, 9}, {12, 9}, {12, 11}, {8, 11}},
+		},
+		{
+			OBB{Center: Vec{10, 10}, HalfExtents: Vec{2, 1}, Rotation: math.Pi / 2},
+			[4]Vec{{11, 8}, {11, 12}, {9, 12}, {9, 8}},
+		},
+		{
+			OBB{HalfExtents: Vec{1, 1}, Rotation: math.Pi / 4},
+			[4]Vec{{0, -math.Sqrt2}, {math.Sqrt2, 0}, {0, math.Sqrt2}, {-math.Sqrt2, 0}},
+		},
+	}
+
+	for _, test := range tests {
+		have := test.b.Corners()
+		for i := range have {
+			if !have[i].EqualApprox(test.want[i]) {
+				t.Fatalf("Corners(%v):\nhave: %v\nwant: %v", test.b, have, test.want)
+			}
+		}
+		if !Polygon(have[:]).IsClockwise() {
+			t.Fatalf("Corners(%v) are not clockwise", test.b)
+		}
+		bounds := test.b.Bounds()
+		wantBounds := Polygon(have[:]).Bounds()
+		if !bounds.Min.EqualApprox(wantBounds.Min) || !bounds.Max.EqualApprox(wantBounds.Max) {
+			t.Fatalf("Bounds(%v):\nhave: %v\nwant: %v", test.b, bounds, wantBounds)
+		}
+	}
+}
+
+func TestOBBContains(t *testing.T) {
+	b := OBB{HalfExtents: Vec{1, 1}, Rotation: math.Pi / 4}
+	tests := []struct {
+		p    Vec
+		want bool
+	}{
+		{Vec{0, 0}, true},
+		{Vec{0, 1.4}, true},
+		{Vec{1.4, 0}, true},
+		{Vec{0, 1.5}, false},
+		{Vec{0.9, 0.9}, false},
+		{Vec{0.7, 0.7}, true},
+	}
+	for _, test := range tests {
+		if have := b.Contains(test.p); have != test.want {
+			t.Fatalf("Contains(%v):\nhave: %v\nwant: %v", test.p, have, test.want)
+		}
+		closest := b.ClosestPoint(test.p)
+		if test.want && closest != test.p && !closest.EqualApprox(test.p) {
+			t.Fatalf("ClosestPoint(%v) for inner point: %v", test.p, closest)
+		}
+	}
+
+	closest := b.ClosestPoint(Vec{5, 5})
+	if want := (Vec{math.Sqrt2 / 2, math.Sqrt2 / 2}); !closest.EqualApprox(want) {
+		t.Fatalf("ClosestPoint(5,5):\nhave: %v\nwant: %v", closest, want)
+	}
+}
+
+func TestOBBIntersects(t *testing.T) {
+	diamond := OBB{Center: Vec{0, 0}, HalfExtents: Vec{1, 1}, Rotation: math.Pi / 4}
+
+	rectTests := []struct {
+		r    Rect
+		want bool
+	}{
+		{Rect{Min: Vec{-0.5, -0.5}, Max: Vec{0.5, 0.5}}, true},
+		{Rect{Min: Vec{1, 1}, Max: Vec{2, 2}}, false},
+		{Rect{Min: Vec{0.6, 0.6}, Max: Vec{2, 2}}, true},
+		{Rect{Min: Vec{1.5, -1}, Max: Vec{2, 1}}, false},
+		{Rect{Min: Vec{1.3, -1}, Max: Vec{2, 1}}, true},
+		{Rect{}, false},
+	}
+	for _, test := range rectTests {
+		if have := diamond.IntersectsRect(test.r); have != test.want {
+			t.Fatalf("IntersectsRect(%v):\nhave: %v\nwant: %v", test.r, have, test.want)
+		}
+	}
+
+	circleTests := []struct {
+		c    Circle
+		want bool
+	}{
+		{Circle{Center: Vec{0, 0}, Radius: 0.1}, true},
+		{Circle{Center: Vec{2, 0}, Radius: 0.5}, false},
+		{Circle{Center: Vec{2, 0}, Radius: 0.7}, true},
+		{Circle{Center: Vec{1, 1}, Radius: 0.2}, false},
+		{Circle{Center: Vec{1, 1}, Radius: 0.8}, true},
+	}
+	for _, test := range circleTests {
+		if have := diamond.IntersectsCircle(test.c); have != test.want {
+			t.Fatalf("IntersectsCircle(%v):\nhave: %v\nwant: %v", test.c, have, test.want)
+		}
+	}
+
+	obbTests := []struct {
+		b    OBB
+		want bool
+	}{
+		{diamond, true},
+		{OBB{Center: Vec{2.5, 0}, HalfExtents: Vec{1, 1}}, false},
+		{OBB{Center: Vec{2.3, 0}, HalfExtents: Vec{1, 1}}, true},
+		{OBB{Center: Vec{1.2, 1.2}, HalfExtents: Vec{1, 0.2}, Rotation: -math.Pi / 4}, false},
+		{OBB{Center: Vec{1.2, 1.2}, HalfExtents: Vec{1, 0.2}, Rotation: math.Pi / 4}, true},
+	}
+	for _, test := range obbTests {
+		if have := diamond.IntersectsOBB(test.b); have != test.want {
+			t.Fatalf("IntersectsOBB(%v):\nhave: %v\nwant: %v", test.b, have, test.want)
+		}
+		if have := test.b.IntersectsOBB(diamond); have != test.want {
+			t.Fatalf("IntersectsOBB(%v) reversed:\nhave: %v\nwant: %v", test.b, have, test.want)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package gmath
+
+import (
+	"bytes"
+	"errors"
+	"math"
+)
+
+// Polygon is a closed shape that is defined by its vertices.
+// The last vertex is implicitly connected to the first one,
+// so there is no need to repeat the first point at the end.
+//
+// The vertices order (winding) is important for some operations.
+// This package uses the screen coordinates (Y axis points down),
+// so a positive [Polygon.SignedArea] means a clockwise winding.
+//
+// Most methods expect the polygon to have at least 3 vertices.
+// Degenerate polygons are handled gracefully, but the results
+// may be not very meaningful (e.g. zero area).
+type Polygon []Vec
+
+// FillRule specifies how the polygon interior is determined.
+type FillRule uint8
+
+const (
+	// FillEvenOdd treats a point as inside if a ray cast from it
+	// crosses the polygon edges an odd number of times.
+	FillEvenOdd FillRule = iota
+
+	// FillNonZero treats a point as inside if the polygon
+	// winds around it a non-zero number of times.
+	FillNonZero
+)
+
+// SignedArea returns the polygon area that has a sign depending on its winding.
+// For clockwise polygons (in screen coordinates) the result is positive.
+//
+// Self-intersecting polygons can have their parts cancel each other out.
+func (p Polygon) SignedArea() float64 {
+	if len(p) < 3 {
+		return 0
+	}
+	area := 0.0
+	prev := p[len(p)-1]
+	for _, v := range p {
+		area += prev.Cross(v)
+		prev = v
+	}
+	return area * 0.5
+}
+
+// Area returns the absolute polygon area.
+// See [Polygon.SignedArea] for the winding-dependent variant.
+func (p Polygon) Area() float64 {
+	return math.Abs(p.SignedArea())
+}
+
+// IsClockwise reports whether polygon vertices are ordered clockwise.
+// It assumes the screen coordinates (Y axis points down).
+func (p Polygon) IsClockwise() bool {
+	return p.SignedArea() > 0
+}
+
+// Reverse changes the polygon winding by reversing its vertices order in-place.
+func (p Polygon) Reverse() {
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+}
+
+// Reversed is like [Polygon.Reverse], but it returns a reversed copy
+// instead of modifying p.
+func (p Polygon) Reversed() Polygon {
+	result := p.Clone()
+	result.Reverse()
+	return result
+}
+
+// Clone returns a copy of the polygon that doesn't share the memory with p.
+func (p Polygon) Clone() Polygon {
+	if p == nil {
+		return nil
+	}
+	result := make(Polygon, len(p))
+	copy(result, p)
+	return result
+}
+
+// Centroid returns the center of mass of the polygon.
+//
+// As a special case, for polygons that have zero area
+// the average of all vertices is returned.
+func (p Polygon) Centroid() Vec {
+	switch len(p) {
+	case 0:
+		return Vec{}
+	case 1:
+		return p[0]
+	}
+
+	// The vertices are translated towards the origin
+	// to improve the precision for the polygons that are far from it.
+	origin := p[0]
+	var sum Vec
+	area := 0.0
+	prev := p[len(p)-1].Sub(origin)
+	for _, v := range p {
+		v = v.Sub(origin)
+		cross := prev.Cross(v)
+		area += cross
+		sum.X += (prev.X + v.X) * cross
+		sum.Y += (prev.Y + v.Y) * cross
+		prev = v
+	}
+
+	if math.Abs(area) < Epsilon {
+		var avg Vec
+		for _, v := range p {
+			avg = avg.Add(v)
+		}
+		return avg.Divf(float64(len(p)))
+	}
+
+	return sum.Divf(3 * area).Add(origin)
+}
+
+// Perimeter returns the total length of the polygon edges.
+func (p Polygon) Perimeter() float64 {
+	if len(p) < 2 {
+		return 0
+	}
+	total := 0.0
+	prev := p[len(p)-1]
+	for _, v := range p {
+		total += prev.DistanceTo(v)
+		prev = v
+	}
+	return total
+}
+
+// ClosestPoint returns the point of the polygon that is closest to p.
+// For a point that is inside the polygon (see [Polygon.Contains]),
+// the point itself is returned.
+func (p Polygon) ClosestPoint(point Vec) Vec {
+	if len(p) == 0 || p.Contains(point) {
+		return point
+	}
+	best := p[0]
+	bestDist := best.DistanceSquaredTo(point)
+	prev := p[len(p)-1]
+	for _, v := range p {
+		c := closestPointOnSegment(prev, v, point)
+		if d := c.DistanceSquaredTo(point); d < bestDist {
+			best = c
+			bestDist = d
+		}
+		prev = v
+	}
+	return best
+}
+
+// Translate moves all polygon vertices by the given offset.
+// The polygon is modified in place.
+func (p Polygon) Translate(offset Vec) {
+	for i := range p {
+		p[i] = p[i].Add(offset)
+	}
+}
+
+// IsEmpty reports whether the polygon has no vertices.
+func (p Polygon) IsEmpty() bool {
+	return len(p) == 0
+}
+
+// Support returns the polygon vertex that is the farthest in the given direction.
+// It implements the [Supporter] interface.
+//
+// For a non-convex polygon, it describes its convex hull.
+// An empty polygon returns a zero vector.
+func (p Polygon) Support(dir Vec) Vec {
+	if len(p) == 0 {
+		return Vec{}
+	}
+	best := p[0]
+	bestDot := best.Dot(dir)
+	for _, v := range p[1:] {
+		if d := v.Dot(dir); d > bestDot {
+			best = v
+			bestDot = d
+		}
+	}
+	return best
+}
+
+// Bounds returns the smallest axis-aligned rectangle that contains all polygon vertices.
+func (p Polygon) Bounds() Rect {
+	if len(p) == 0 {
+		return Rect{}
+	}
+	r := Rect{Min: p[0], Max: p[0]}
+	for _, v := range p[1:] {
+		r.Min.X = math.Min(r.Min.X, v.X)
+		r.Min.Y = math.Min(r.Min.Y, v.Y)
+		r.Max.X = math.Max(r.Max.X, v.X)
+		r.Max.Y = math.Max(r.Max.Y, v.Y)
+	}
+	return r
+}
+
+// Contains reports whether the point is inside the polygon.
+// It uses the [FillEvenOdd] rule.
+//
+// The points that lie exactly on the polygon edges
+// may be reported either way.
+func (p Polygon) Contains(point Vec) bool {
+	return p.ContainsWithRule(point, FillEvenOdd)
+}
+
+// ContainsWithRule is like [Polygon.Contains], but
+// it allows the caller to specify the fill rule.
+//
+// The rules only give different results for self-intersecting polygons.
+func (p Polygon) ContainsWithRule(point Vec, rule FillRule) bool {
+	if len(p) < 3 {
+		return false
+	}
+	if rule == FillNonZero {
+		return p.windingNumber(point) != 0
+	}
+
+	inside := false
+	prev := p[len(p)-1]
+	for _, v := range p {
+		if (v.Y > point.Y) != (prev.Y > point.Y) {
+			x := (prev.X-v.X)*(point.Y-v.Y)/(prev.Y-v.Y) + v.X
+			if point.X < x {
+				inside = !inside
+			}
+		}
+		prev = v
+	}
+	return inside
+}
+
+func (p Polygon) windingNumber(point Vec) int {
+	wn := 0
+	prev := p[len(p)-1]
+	for _, v := range p {
+		if prev.Y <= point.Y {
+			if v.Y > point.Y && orientation(prev, v, point) > 0 {
+				wn++
+			}
+		} else {
+			if v.Y <= point.Y && orientation(prev, v, point) < 0 {
+				wn--
+			}
+		}
+		prev = v
+	}
+	return wn
+}
+
+// IsConvex reports whether the polygon is convex.
+//
+// Collinear vertices are allowed and don't affect the result.
+// Self-intersecting polygons are never convex.
+func (p Polygon) IsConvex() bool {
+	if len(p) < 3 {
+		return false
+	}
+
+	sign := 0
+	xFlips := 0
+	yFlips := 0
+	prevDir := p[0].Sub(p[len(p)-1])
+	xSign := fsign(prevDir.X)
+	ySign := fsign(prevDir.Y)
+	for i := range p {
+		next := p[(i+1)%len(p)]
+		dir := next.Sub(p[i])
+
+		// A convex polygon changes its direction by each axis
+		// exactly two times. More changes mean that it winds
+		// around itself more than once.
+		if s := fsign(dir.X); s != 0 {
+			if xSign != 0 && s != xSign {
+				xFlips++
+			}
+			xSign = s
+		}
+		if s := fsign(dir.Y); s != 0 {
+			if ySign != 0 && s != ySign {
+				yFlips++
+			}
+			ySign = s
+		}
+
+		if s := fsign(prevDir.Cross(dir)); s != 0 {
+			if sign != 0 && s != sign {
+				return false
+			}
+			sign = s
+		}
+		prevDir = dir
+	}
+
+	return sign != 0 && xFlips <= 2 && yFlips <= 2
+}
+
+// IsSimple reports whether the polygon has no self-intersections.
+// The adjacent edges are permitted to share their common vertex.
+//
+// This check has O(n^2) complexity.
+func (p Polygon) IsSimple() bool {
+	n := len(p)
+	if n < 3 {
+		return false
+	}
+
+	for i := 0; i < n; i++ {
+		a1 := p[i]
+		a2 := p[(i+1)%n]
+		// The adjacent edges can only overlap if they're collinear
+		// and the polygon turns back on itself.
+		a3 := p[(i+2)%n]
+		if orientation(a1, a2, a3) == 0 && a1.Sub(a2).Dot(a3.Sub(a2)) > 0 {
+			return false
+		}
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue // Adjacent edges
+			}
+			if segmentsIntersect(a1, a2, p[j], p[(j+1)%n]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (p Polygon) MarshalJSON() ([]byte, error) {
+	buf := make([]byte, 0, 2+len(p)*16)
+	buf = append(buf, '[')
+	for i, v := range p {
+		if i != 0 {
+			buf = append(buf, ',')
+		}
+		buf = v.appendJSON(buf)
+	}
+	buf = append(buf, ']')
+	return buf, nil
+}
+
+func (p *Polygon) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) == 0 || data[0] != '[' {
+		return errors.New("missing opening '['")
+	}
+	if data[len(data)-1] != ']' {
+		return errors.New("missing closing ']'")
+	}
+
+	data = bytes.TrimSpace(data[1 : len(data)-1])
+	result := (*p)[:0]
+	for len(data) != 0 {
+		end := bytes.IndexByte(data, ']')
+		if end == -1 {
+			return errors.New("missing closing ']' of a vertex")
+		}
+		var v Vec
+		if err := v.UnmarshalJSON(data[:end+1]); err != nil {
+			return err
+		}
+		result = append(result, v)
+		data = bytes.TrimSpace(data[end+1:])
+		if len(data) == 0 {
+			break
+		}
+		if data[0] != ',' {
+			return errors.New("missing ',' between vertices")
+		}
+		data = bytes.TrimSpace(data[1:])
+		if len(data) == 0 {
+			return errors.New("unexpected trailing ','")
+		}
+	}
+	if result == nil {
+		result = Polygon{}
+	}
+
+	*p = result
+	return nil
+}
+
+// orientation returns a sign of the (b-a)x(c-a) cross product.
+// With the screen coordinates, a positive result means that
+// the a->b->c turn is clockwise.
+// The near-zero results are reported as 0 (collinear).
+func orientation(a, b, c Vec) int {
+	return fsign(b.Sub(a).Cross(c.Sub(a)))
+}
+
+func fsign(x float64) int {
+	switch {
+	case x > Epsilon:
+		return 1
+	case x < -Epsilon:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// onSegment reports whether p lies inside a bounding box of the a-b segment.
+// It's only meaningful for the points that are known to be collinear with a-b.
+func onSegment(a, b, p Vec) bool {
+	return math.Min(a.X, b.X)-Epsilon <= p.X && p.X <= math.Max(a.X, b.X)+Epsilon &&
+		math.Min(a.Y, b.Y)-Epsilon <= p.Y && p.Y <= math.Max(a.Y, b.Y)+Epsilon
+}
+
+// segmentsIntersect reports whether a1-a2 and b1-b2 segments have any common point.
+// The touching endpoints count as an intersection.
+func segmentsIntersect(a1, a2, b1, b2 Vec) bool {
+	o1 := orientation(a1, a2, b1)
+	o2 := orientation(a1, a2, b2)
+	o3 := orientation(b1, b2, a1)
+	o4 := orientation(b1, b2, a2)
+
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+
+	return (o1 == 0 && onSegment(a1, a2, b1)) ||
+		(o2 == 0 && onSegment(a1, a2, b2)) ||
+		(o3 == 0 && onSegment(b1, b2, a1)) ||
+		(o4 == 0 && onSegment(b1, b2, a2))
+}
@@ -0,0 +1,303 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClipPolygons(t *testing.T) {
+	rect := func(x1, y1, x2, y2 float64) Polygon {
+		return Polygon{{x1, y1}, {x2, y1}, {x2, y2}, {x1, y2}}
+	}
+	square := PolygonWithHoles{Outer: rect(0, 0, 10, 10)}
+	shifted := PolygonWithHoles{Outer: rect(5, 5, 15, 15)}
+	adjacent := PolygonWithHoles{Outer: rect(10, 0, 20, 10)}
+	corner := PolygonWithHoles{Outer: rect(10, 10, 20, 20)}
+	inner := PolygonWithHoles{Outer: rect(3, 3, 7, 7).Reversed()}
+	frame := PolygonWithHoles{Outer: rect(0, 0, 10, 10), Holes: []Polygon{rect(2, 2, 8, 8)}}
+	far := PolygonWithHoles{Outer: rect(100, 100, 110, 110)}
+
+	type result struct {
+		count int
+		holes int
+		area  float64
+	}
+	tests := []struct {
+		name string
+		a    PolygonWithHoles
+		b    PolygonWithHoles
+		want [4]result // union, intersection, difference, xor
+	}{
+		{
+			name: "overlap",
+			a:    square,
+			b:    shifted,
+			want: [4]result{{1, 0, 175}, {1, 0, 25}, {1, 0, 75}, {2, 0, 150}},
+		},
+		{
+			name: "identical",
+			a:    square,
+			b:    square,
+			want: [4]result{{1, 0, 100}, {1, 0, 100}, {0, 0, 0}, {0, 0, 0}},
+		},
+		{
+			name: "shared_edge",
+			a:    square,
+			b:    adjacent,
+			want: [4]result{{1, 0, 200}, {0, 0, 0}, {1, 0, 100}, {1, 0, 200}},
+		},
+		{
+			name: "shared_vertex",
+			a:    square,
+			b:    corner,
+			want: [4]result{{2, 0, 200}, {0, 0, 0}, {1, 0, 100}, {2, 0, 200}},
+		},
+		{
+			name: "inside",
+			a:    square,
+			b:    inner,
+			want: [4]result{{1, 0, 100}, {1, 0, 16}, {1, 1, 84}, {1, 1, 84}},
+		},
+		{
+			name: "frame_and_inner",
+			a:    frame,
+			b:    inner,
+			want: [4]result{{2, 1, 80}, {0, 0, 0}, {1, 1, 64}, {2, 1, 80}},
+		},
+		{
+			name: "frame_and_square",
+			a:    frame,
+			b:    square,
+			want: [4]result{{1, 0, 100}, {1, 1, 64}, {0, 0, 0}, {1, 0, 36}},
+		},
+		{
+			name: "disjoint",
+			a:    square,
+			b:    far,
+			want: [4]result{{2, 0, 200}, {0, 0, 0}, {1, 0, 100}, {2, 0, 200}},
+		},
+	}
+
+	ops := [4]ClipOp{ClipUnion, ClipIntersection, ClipDifference, ClipXor}
+	for _, test := range tests {
+		for i, op := range ops {
+			have := ClipPolygons(op, []PolygonWithHoles{test.a}, []PolygonWithHoles{test.b})
+			area := 0.0
+			holes := 0
+			for _, p := range have {
+				area += p.Area()
+				holes += len(p.Holes)
+			}
+			want := test.want[i]
+			if len(have) != want.count || holes != want.holes || !EqualApprox(area, want.area) {
+				t.Fatalf("%s op=%d:\nhave: count=%d holes=%d area=%v\nwant: count=%d holes=%d area=%v\n%v",
+					test.name, op, len(have), holes, area, want.count, want.holes, want.area, have)
+			}
+		}
+	}
+}
+
+func TestClipPolygonsRandomized(t *testing.T) {
+	var rng Rand
+	rng.SetSeed(7)
+
+	// Star-shaped polygons are always simple.
+	randomStar := func(center Vec, radius float64, snap bool) Polygon {
+		n := rng.IntRange(3, 12)
+		p := make(Polygon, n)
+		for i := range p {
+			angle := Rad(2 * math.Pi * (float64(i) + rng.FloatRange(0, 0.8)) / float64(n))
+			v := center.MoveInDirection(rng.FloatRange(radius*0.3, radius), angle)
+			if snap {
+				// Integer coordinates provoke the degenerate cases:
+				// shared vertices, collinear edges, etc.
+				v = v.Rounded()
+			}
+			p[i] = v
+		}
+		if rng.Bool() {
+			p.Reverse()
+		}
+		return p
+	}
+	randomRect := func() Polygon {
+		x := float64(rng.IntRange(0, 10))
+		y := float64(rng.IntRange(0, 10))
+		w := float64(rng.IntRange(1, 10))
+		h := float64(rng.IntRange(1, 10))
+		return Polygon{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}
+	}
+	randomShape := func() PolygonWithHoles {
+		switch rng.IntRange(0, 3) {
+		case 0:
+			return PolygonWithHoles{Outer: randomRect()}
+		case 1:
+			outer := randomRect()
+			b := outer.Bounds()
+			if b.Width() < 3 || b.Height() < 3 {
+				return PolygonWithHoles{Outer: outer}
+			}
+			hole := Polygon{
+				b.Min.Add(Vec{1, 1}),
+				{b.Max.X - 1, b.Min.Y + 1},
+				b.Max.Sub(Vec{1, 1}),
+				{b.Min.X + 1, b.Max.Y - 1},
+			}
+			return PolygonWithHoles{Outer: outer, Holes: []Polygon{hole}}
+		case 2:
+			center := Vec{X: rng.FloatRange(5, 15), Y: rng.FloatRange(5, 15)}
+			return PolygonWithHoles{Outer: randomStar(center, rng.FloatRange(2, 10), true)}
+		default:
+			center := Vec{X: rng.FloatRange(5, 15), Y: rng.FloatRange(5, 15)}
+			return PolygonWithHoles{Outer: randomStar(center, rng.FloatRange(2, 10), false)}
+		}
+	}
+
+	totalArea := func(polygons []PolygonWithHoles) float64 {
+		area := 0.0
+		for _, p := range polygons {
+			area += p.Area()
+		}
+		return area
+	}
+	contains := func(polygons []PolygonWithHoles, pos Vec) bool {
+		for _, p := range polygons {
+			if p.Contains(pos) {
+				return true
+			}
+		}
+		return false
+	}
+	// nearBoundary reports whether pos is too close to any polygon edge
+	// to be used as a reliable sample point.
+	nearBoundary := func(pos Vec, shapes ...PolygonWithHoles) bool {
+		for _, s := range shapes {
+			rings := append([]Polygon{s.Outer}, s.Holes...)
+			for _, ring := range rings {
+				prev := ring[len(ring)-1]
+				for _, v := range ring {
+					if closestPointOnSegment(prev, v, pos).DistanceTo(pos) < 0.001 {
+						return true
+					}
+					prev = v
+				}
+			}
+		}
+		return false
+	}
+
+	const numRounds = 500
+	const numSamples = 50
+	for round := 0; round < numRounds; round++ {
+		a := randomShape()
+		b := randomShape()
+		if !a.Outer.IsSimple() || !b.Outer.IsSimple() {
+			continue
+		}
+		subject := []PolygonWithHoles{a}
+		clip := []PolygonWithHoles{b}
+
+		union := ClipPolygons(ClipUnion, subject, clip)
+		intersection := ClipPolygons(ClipIntersection, subject, clip)
+		difference := ClipPolygons(ClipDifference, subject, clip)
+		xor := ClipPolygons(ClipXor, subject, clip)
+
+		areaA := a.Area()
+		areaB := b.Area()
+		areaU := totalArea(union)
+		areaI := totalArea(intersection)
+		areaD := totalArea(difference)
+		areaX := totalArea(xor)
+
+		const tolerance = 1e-6
+		if math.Abs(areaU-(areaA+areaB-areaI)) > tolerance {
+			t.Fatalf("round %d: union area %v != %v+%v-%v\na=%v\nb=%v", round, areaU, areaA, areaB, areaI, a, b)
+		}
+		if math.Abs(areaD-(areaA-areaI)) > tolerance {
+			t.Fatalf("round %d: difference area %v != %v-%v\na=%v\nb=%v", round, areaD, areaA, areaI, a, b)
+		}
+		if math.Abs(areaX-(areaU-areaI)) > tolerance {
+			t.Fatalf("round %d: xor area %v != %v-%v\na=%v\nb=%v", round, areaX, areaU, areaI, a, b)
+		}
+
+		for _, results := range [][]PolygonWithHoles{union, intersection, difference, xor} {
+			for _, p := range results {
+				if !p.Outer.IsClockwise() {
+					t.Fatalf("round %d: outer polygon is not clockwise: %v", round, p.Outer)
+				}
+				for _, h := range p.Holes {
+					if h.IsClockwise() {
+						t.Fatalf("round %d: hole is clockwise: %v", round, h)
+					}
+				}
+			}
+		}
+
+		for i := 0; i < numSamples; i++ {
+			pos := Vec{X: rng.FloatRange(-2, 22), Y: rng.FloatRange(-2, 22)}
+			if nearBoundary(pos, a, b) {
+				continue
+			}
+			inA := a.Contains(pos)
+			inB := b.Contains(pos)
+			checks := []struct {
+				name    string
+				result  []PolygonWithHoles
+				wantsIn bool
+			}{
+				{"union", union, inA || inB},
+				{"intersection", intersection, inA && inB},
+				{"difference", difference, inA && !inB},
+				{"xor", xor, inA != inB},
+			}
+			for _, c := range checks {
+				if contains(c.result, pos) != c.wantsIn {
+					t.Fatalf("round %d: %s containment of %v mismatch (want %v)\na=%v\nb=%v\nresult=%v",
+						round, c.name, pos, c.wantsIn, a, b, c.result)
+				}
+			}
+		}
+	}
+}
+
+func TestClipPolygonsLarge(t *testing.T) {
+	circle := func(center Vec, r float64, n int) Polygon {
+		p := make(Polygon, n)
+		for i := range p {
+			p[i] = center.MoveInDirection(r, Rad(2*math.Pi*float64(i)/float64(n)))
+		}
+		return p
+	}
+	const r = 10.0
+	const d = 10.0
+	a := PolygonWithHoles{Outer: circle(Vec{}, r, 2000)}
+	b := PolygonWithHoles{Outer: circle(Vec{X: d}, r, 2000)}
+
+	lens := 2*r*r*math.Acos(d/(2*r)) - (d/2)*math.Sqrt(4*r*r-d*d)
+	intersection := a.Intersection(b)
+	if len(intersection) != 1 || math.Abs(intersection[0].Area()-lens) > 0.01 {
+		t.Fatalf("intersection area mismatch: have %v, want %v", intersection, lens)
+	}
+	union := a.Union(b)
+	if want := a.Area() + b.Area() - intersection[0].Area(); len(union) != 1 || !EqualApprox(union[0].Area(), want) {
+		t.Fatalf("union area mismatch: have %v, want %v", union, want)
+	}
+}
+
+func BenchmarkClipPolygons(b *testing.B) {
+	circle := func(center Vec, r float64, n int) Polygon {
+		p := make(Polygon, n)
+		for i := range p {
+			p[i] = center.MoveInDirection(r, Rad(2*math.Pi*float64(i)/float64(n)))
+		}
+		return p
+	}
+	subject := []PolygonWithHoles{{Outer: circle(Vec{}, 10, 5000)}}
+	clip := []PolygonWithHoles{{Outer: circle(Vec{X: 10}, 10, 5000)}}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ClipPolygons(ClipIntersection, subject, clip)
+	}
+}
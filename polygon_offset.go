@@ -0,0 +1,167 @@
+package gmath
+
+import (
+	"math"
+)
+
+// JoinStyle specifies how the polygon corners are connected when it's being offset.
+type JoinStyle uint8
+
+const (
+	// JoinMiter extends the edges until they meet.
+	// If the resulting corner is too sharp (see [OffsetOptions.MiterLimit]),
+	// it's squared off like with [JoinSquare].
+	JoinMiter JoinStyle = iota
+
+	// JoinRound connects the edges with a circular arc.
+	JoinRound
+
+	// JoinSquare cuts the corner at the offset distance.
+	JoinSquare
+)
+
+// OffsetOptions configures the polygon offsetting.
+// A zero value is a valid configuration that uses mitered joins.
+type OffsetOptions struct {
+	Join JoinStyle
+
+	// MiterLimit is a maximum ratio between the miter length
+	// and the offset distance.
+	// Values below 1 are treated as a default value of 2.
+	MiterLimit float64
+
+	// ArcTolerance is a maximum distance between the ideal
+	// arc and its approximation for the [JoinRound] joins.
+	// Zero means a default value of 0.25.
+	ArcTolerance float64
+}
+
+// Offset grows (if delta is positive) or shrinks (if delta is negative)
+// the polygon by the specified distance.
+//
+// The polygon can be concave and use any winding order.
+// Shrinking the polygon can split it into several polygons,
+// while growing it can merge its parts together and create holes,
+// this is why the result is a slice of [PolygonWithHoles].
+//
+// The returned slice is empty if the polygon collapses completely.
+func (p Polygon) Offset(delta float64, opts OffsetOptions) []PolygonWithHoles {
+	return PolygonWithHoles{Outer: p}.Offset(delta, opts)
+}
+
+// Offset is like [Polygon.Offset], but it offsets the holes as well.
+// The holes shrink when the polygon grows and vice versa.
+func (p PolygonWithHoles) Offset(delta float64, opts OffsetOptions) []PolygonWithHoles {
+	if opts.MiterLimit < 1 {
+		opts.MiterLimit = 2
+	}
+	if opts.ArcTolerance <= 0 {
+		opts.ArcTolerance = 0.25
+	}
+
+	rings := make([]Polygon, 0, len(p.Holes)+1)
+	rings = append(rings, offsetRing(p.Outer, true, delta, opts))
+	for _, h := range p.Holes {
+		rings = append(rings, offsetRing(h, false, delta, opts))
+	}
+
+	// The offset rings self-intersect at the concave corners and
+	// wherever the shape is split or merged; keep only the areas
+	// that are covered by the positive winding.
+	return overlayPolygons([2][]Polygon{rings}, func(wind [2]int) bool {
+		return wind[0] > 0
+	})
+}
+
+func offsetRing(ring Polygon, outer bool, delta float64, opts OffsetOptions) Polygon {
+	// Remove the duplicated points first: they have no direction.
+	points := make(Polygon, 0, len(ring))
+	for _, v := range ring {
+		if len(points) == 0 || points[len(points)-1] != v {
+			points = append(points, v)
+		}
+	}
+	for len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	if len(points) < 3 {
+		return nil
+	}
+	if points.IsClockwise() != outer {
+		points.Reverse()
+	}
+
+	result := make(Polygon, 0, len(points)*2)
+	for i, cur := range points {
+		prev := points[(i+len(points)-1)%len(points)]
+		next := points[(i+1)%len(points)]
+		d1 := cur.Sub(prev).Normalized()
+		d2 := next.Sub(cur).Normalized()
+		// The outward normals: the filled area is on the other side.
+		n1 := Vec{X: d1.Y, Y: -d1.X}
+		n2 := Vec{X: d2.Y, Y: -d2.X}
+
+		cross := d1.Cross(d2)
+		dot := d1.Dot(d2)
+		switch {
+		case math.Abs(cross) < Epsilon && dot > 0:
+			// Collinear edges, no join required.
+			result = append(result, cur.Add(n1.Mulf(delta)))
+		case cross*delta > 0 || (math.Abs(cross) < Epsilon && dot < 0):
+			// The offset edges diverge here.
+			result = appendOffsetJoin(result, cur, d1, d2, n1, n2, delta, opts)
+		default:
+			// The offset edges overlap here.
+			// Going through the original vertex keeps the winding right;
+			// the excessive loops are removed later.
+			result = append(result, cur.Add(n1.Mulf(delta)), cur, cur.Add(n2.Mulf(delta)))
+		}
+	}
+
+	return result
+}
+
+func appendOffsetJoin(dst Polygon, cur, d1, d2, n1, n2 Vec, delta float64, opts OffsetOptions) Polygon {
+	join := opts.Join
+	if join == JoinMiter {
+		cosTheta := n1.Dot(n2)
+		if 1+cosTheta > Epsilon && math.Sqrt(2/(1+cosTheta)) <= opts.MiterLimit {
+			return append(dst, cur.Add(n1.Add(n2).Mulf(delta/(1+cosTheta))))
+		}
+		join = JoinSquare
+	}
+
+	if join == JoinRound {
+		angle := math.Atan2(n1.Cross(n2), n1.Dot(n2))
+		if math.Abs(math.Abs(angle)-math.Pi) < Epsilon {
+			// A 180 degrees turn: the arc should go around the vertex.
+			angle = math.Copysign(math.Pi, delta)
+		}
+		radius := math.Abs(delta)
+		stepAngle := math.Pi / 2
+		if opts.ArcTolerance < radius {
+			stepAngle = math.Min(stepAngle, 2*math.Acos(1-opts.ArcTolerance/radius))
+		}
+		steps := int(math.Ceil(math.Abs(angle) / stepAngle))
+		if steps < 1 {
+			steps = 1
+		}
+		for i := 0; i <= steps; i++ {
+			v := n1.Rotated(Rad(angle * float64(i) / float64(steps)))
+			dst = append(dst, cur.Add(v.Mulf(delta)))
+		}
+		return dst
+	}
+
+	// JoinSquare: the corner is cut by a line that is perpendicular
+	// to the bisector and is located at the offset distance.
+	u := n1.Add(n2).Normalized()
+	if u.IsZero() {
+		u = d1
+	}
+	s1 := delta * (1 - n1.Dot(u)) / d1.Dot(u)
+	s2 := delta * (1 - n2.Dot(u)) / d2.Dot(u)
+	return append(dst,
+		cur.Add(n1.Mulf(delta)).Add(d1.Mulf(s1)),
+		cur.Add(n2.Mulf(delta)).Add(d2.Mulf(s2)))
+}
@@ -0,0 +1,108 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolygonOffset(t *testing.T) {
+	square := Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	lshape := Polygon{{0, 0}, {40, 0}, {40, 10}, {10, 10}, {10, 40}, {0, 40}}
+	// Two 10x10 squares connected with a 2 units thick bridge.
+	dumbbell := Polygon{
+		{0, 0}, {10, 0}, {10, 4}, {20, 4}, {20, 0}, {30, 0},
+		{30, 10}, {20, 10}, {20, 6}, {10, 6}, {10, 10}, {0, 10},
+	}
+	// A U shape with a 2 units wide gap.
+	ushape := Polygon{{0, 0}, {22, 0}, {22, 22}, {12, 22}, {12, 10}, {10, 10}, {10, 22}, {0, 22}}
+	// A square with a cavity that is connected to the outside with a thin slit.
+	cavity := Polygon{
+		{0, 0}, {30, 0}, {30, 30}, {15.5, 30}, {15.5, 20}, {20, 20},
+		{20, 10}, {10, 10}, {10, 20}, {14.5, 20}, {14.5, 30}, {0, 30},
+	}
+
+	tests := []struct {
+		name      string
+		p         Polygon
+		delta     float64
+		opts      OffsetOptions
+		wantArea  float64
+		wantCount int
+		wantHoles int
+	}{
+		{"square_zero", square, 0, OffsetOptions{}, 100, 1, 0},
+		{"square_miter", square, 2, OffsetOptions{}, 196, 1, 0},
+		{"square_miter_ccw", square.Reversed(), 2, OffsetOptions{}, 196, 1, 0},
+		{"square_square", square, 2, OffsetOptions{Join: JoinSquare}, 196 - 4*2*(2-2*math.Sqrt2)*(2-2*math.Sqrt2)/2, 1, 0},
+		{"square_miter_limit", square, 2, OffsetOptions{MiterLimit: 1.1}, 196 - 4*2*(2-2*math.Sqrt2)*(2-2*math.Sqrt2)/2, 1, 0},
+		{"square_round", square, 2, OffsetOptions{Join: JoinRound, ArcTolerance: 0.001}, 100 + 4*10*2 + math.Pi*4, 1, 0},
+		{"square_shrink", square, -2, OffsetOptions{}, 36, 1, 0},
+		{"square_shrink_round", square, -2, OffsetOptions{Join: JoinRound}, 36, 1, 0},
+		{"square_collapse", square, -6, OffsetOptions{}, 0, 0, 0},
+		{"lshape_grow", lshape, 1, OffsetOptions{}, 42*12 + 12*30, 1, 0},
+		{"lshape_shrink", lshape, -1, OffsetOptions{}, 38*8 + 8*30, 1, 0},
+		{"dumbbell_shrink", dumbbell, -1.5, OffsetOptions{}, 2 * 7 * 7, 2, 0},
+		{"dumbbell_shrink_tiny", dumbbell, -0.5, OffsetOptions{}, 2*9*9 + 11*1, 1, 0},
+		{"ushape_grow", ushape, 1.5, OffsetOptions{}, 25 * 25, 1, 0},
+		{"cavity_grow", cavity, 1, OffsetOptions{}, 32*32 - 8*8, 1, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.p.Offset(test.delta, test.opts)
+			if len(result) != test.wantCount {
+				t.Fatalf("polygons count mismatch:\nhave: %d\nwant: %d\n%v", len(result), test.wantCount, result)
+			}
+			area := 0.0
+			holes := 0
+			for _, p := range result {
+				area += p.Area()
+				holes += len(p.Holes)
+				if !p.Outer.IsClockwise() {
+					t.Fatalf("outer polygon is not clockwise: %v", p.Outer)
+				}
+				if !p.Outer.IsSimple() {
+					t.Fatalf("outer polygon is not simple: %v", p.Outer)
+				}
+			}
+			if holes != test.wantHoles {
+				t.Fatalf("holes count mismatch:\nhave: %d\nwant: %d", holes, test.wantHoles)
+			}
+			if math.Abs(area-test.wantArea) > 0.01 {
+				t.Fatalf("area mismatch:\nhave: %v\nwant: %v", area, test.wantArea)
+			}
+		})
+	}
+}
+
+func TestPolygonOffsetHoles(t *testing.T) {
+	p := PolygonWithHoles{
+		Outer: Polygon{{0, 0}, {20, 0}, {20, 20}, {0, 20}},
+		Holes: []Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}}},
+	}
+
+	grown := p.Offset(1, OffsetOptions{})
+	if len(grown) != 1 || len(grown[0].Holes) != 1 {
+		t.Fatalf("unexpected grow result: %v", grown)
+	}
+	if want := 22.0*22 - 8*8; !EqualApprox(grown[0].Area(), want) {
+		t.Fatalf("grow area mismatch:\nhave: %v\nwant: %v", grown[0].Area(), want)
+	}
+	if grown[0].Holes[0].IsClockwise() {
+		t.Fatalf("hole is clockwise: %v", grown[0].Holes[0])
+	}
+
+	// The hole disappears completely.
+	grown = p.Offset(6, OffsetOptions{})
+	if len(grown) != 1 || len(grown[0].Holes) != 0 {
+		t.Fatalf("unexpected grow result: %v", grown)
+	}
+
+	shrunk := p.Offset(-1, OffsetOptions{})
+	if len(shrunk) != 1 || len(shrunk[0].Holes) != 1 {
+		t.Fatalf("unexpected shrink result: %v", shrunk)
+	}
+	if want := 18.0*18 - 12*12; !EqualApprox(shrunk[0].Area(), want) {
+		t.Fatalf("shrink area mismatch:\nhave: %v\nwant: %v", shrunk[0].Area(), want)
+	}
+}
@@ -0,0 +1,790 @@
+package gmath
+
+import (
+	"math"
+	"sort"
+)
+
+// polygonOverlay computes the regions covered by a set of polygon rings.
+//
+// It's a foundation for the polygon offsetting and boolean operations.
+// The algorithm follows the Martinez-Rueda approach and uses two sweeps along the X axis.
+//
+// The first sweep splits the segments at their intersection points (see splitSegments).
+// The resulting pieces are turned into the graph edges; the pieces that
+// coincide are merged into a single edge with their winding contributions summed up,
+// so the overlapping edges don't need any special handling.
+//
+// The second sweep assigns the winding numbers to both sides of every
+// edge (see selectEdges). The edges that separate the "inside" and "outside"
+// areas form the result.
+//
+// There can be up to two operands (groups of rings), the winding numbers
+// are tracked for each operand separately.
+//
+// Both sweeps take O((n+k)*log(n)) time, where n is the number of segments
+// and k is the number of intersections. The sweep line status is a sorted slice,
+// so its insertions and removals are linear in the status size, but it only
+// holds the edges that cross the sweep line, so it's small for the typical inputs.
+type polygonOverlay struct {
+	segments [][2]Vec
+	events   []overlayEvent
+	queue    []int32
+	status   []int32
+
+	vertices []Vec
+	cells    map[[2]int64][]int
+
+	edges      []overlayEdge
+	edgeByKey  map[[2]int]int
+	directed   []overlayDirectedEdge
+	outgoing   [][]int
+	usedEdges  []bool
+	vertexTemp []Vec
+}
+
+// overlayEvent is an endpoint of a segment piece.
+// The left endpoint has the smaller X (or the smaller Y for the vertical pieces).
+type overlayEvent struct {
+	point Vec
+
+	// other is the opposite endpoint of the piece.
+	// It changes when the piece is split.
+	other int32
+
+	// segment is the index of the original segment that contains the piece.
+	// The intersections are computed for the original segments,
+	// so the round-off errors of the split points don't accumulate.
+	segment int32
+
+	left bool
+
+	// merged is set for the left endpoint of a piece that coincides
+	// with another status piece; its winding contributions are moved there.
+	merged bool
+
+	// wind is a sum of the winding contributions of the piece
+	// (per operand) for the left->right direction.
+	// Only the left endpoint wind is used.
+	wind [2]int
+}
+
+type overlayEdge struct {
+	// a and b are vertex indices; a < b.
+	a int
+	b int
+
+	// wind is a sum of the winding contributions of the
+	// coinciding segments (per operand) for the a->b direction.
+	// The opposite direction contributes negatively.
+	wind [2]int
+
+	// above is the winding number of the area above the edge (with the greater Y).
+	// For the vertical edges, it's the area to the left of the edge.
+	above [2]int
+}
+
+type overlayDirectedEdge struct {
+	from int
+	to   int
+}
+
+const overlaySnapDist = 1e-7
+
+// overlayPolygons returns the regions for which the inside function reports true.
+// The inside function is called with the winding numbers for every operand.
+//
+// A winding number is positive inside the clockwise rings and
+// negative inside the counter-clockwise rings.
+func overlayPolygons(operands [2][]Polygon, inside func(wind [2]int) bool) []PolygonWithHoles {
+	o := polygonOverlay{
+		cells:     make(map[[2]int64][]int),
+		edgeByKey: make(map[[2]int]int),
+	}
+	for operand, rings := range operands {
+		for _, ring := range rings {
+			o.addRing(ring, operand)
+		}
+	}
+	o.splitSegments()
+	o.buildEdges()
+	o.selectEdges(inside)
+	return assembleRegions(o.traceRings())
+}
+
+func (o *polygonOverlay) addRing(ring Polygon, operand int) {
+	if len(ring) < 3 {
+		return
+	}
+	prev := ring[len(ring)-1]
+	for _, v := range ring {
+		if v != prev {
+			o.addSegment(prev, v, operand)
+		}
+		prev = v
+	}
+}
+
+func (o *polygonOverlay) addSegment(a, b Vec, operand int) {
+	a = overlayRound(a)
+	b = overlayRound(b)
+	if a == b {
+		return
+	}
+	var wind [2]int
+	wind[operand] = 1
+	if overlayPointLess(b, a) {
+		a, b = b, a
+		wind[operand] = -1
+	}
+	segment := int32(len(o.segments))
+	o.segments = append(o.segments, [2]Vec{a, b})
+	l := o.newEvent(overlayEvent{point: a, left: true, segment: segment, wind: wind})
+	r := o.newEvent(overlayEvent{point: b, other: l, segment: segment})
+	o.events[l].other = r
+}
+
+func (o *polygonOverlay) newEvent(e overlayEvent) int32 {
+	id := int32(len(o.events))
+	o.events = append(o.events, e)
+	return id
+}
+
+// splitSegments splits the segments at their intersection points.
+//
+// It's a Bentley-Ottmann sweep: the status holds the pieces that cross
+// the sweep line ordered from the bottom to the top, and only the pieces
+// that become adjacent in the status are tested for the intersections.
+// The intersecting pieces are split at the intersection point,
+// so the status pieces never cross each other.
+func (o *polygonOverlay) splitSegments() {
+	for i := range o.events {
+		o.pushEvent(int32(i))
+	}
+
+	for len(o.queue) != 0 {
+		id := o.popEvent()
+		if o.events[id].left {
+			i := o.pieceInsertIndex(id)
+			if o.mergePiece(id, i) {
+				continue
+			}
+			o.status = append(o.status, 0)
+			copy(o.status[i+1:], o.status[i:])
+			o.status[i] = id
+			if i+1 < len(o.status) {
+				o.intersectPieces(id, o.status[i+1])
+			}
+			if i > 0 {
+				o.intersectPieces(o.status[i-1], id)
+			}
+			continue
+		}
+
+		if o.events[o.events[id].other].merged {
+			continue
+		}
+		i := o.pieceIndex(o.events[id].other, o.events[id].point)
+		if i == -1 {
+			continue
+		}
+		o.status = append(o.status[:i], o.status[i+1:]...)
+		if i > 0 && i < len(o.status) {
+			o.intersectPieces(o.status[i-1], o.status[i])
+		}
+	}
+}
+
+// mergePiece tries to merge the l piece into the status piece that
+// starts at the same point and goes in the same direction.
+// The longer piece is split, so their common parts become a single piece.
+// Without that, the coinciding pieces could separate the other
+// status pieces from one of them, hiding some intersections.
+//
+// The i is the status index where l would be inserted.
+func (o *polygonOverlay) mergePiece(l int32, i int) bool {
+	lp := o.events[l].point
+	for j := i - 1; j <= i; j++ {
+		if j < 0 || j >= len(o.status) {
+			continue
+		}
+		s := o.status[j]
+		if o.events[s].point != lp || !o.collinearPieces(s, l) {
+			continue
+		}
+		sr := o.events[s].other
+		lr := o.events[l].other
+		if overlayPointLess(o.events[lr].point, o.events[sr].point) {
+			o.splitPiece(s, o.events[lr].point)
+		} else {
+			o.splitPiece(l, o.events[sr].point)
+		}
+		sr = o.events[s].other
+		lr = o.events[l].other
+		if o.events[sr].point.DistanceSquaredTo(o.events[lr].point) > overlaySnapDist*overlaySnapDist {
+			continue
+		}
+		o.events[s].wind[0] += o.events[l].wind[0]
+		o.events[s].wind[1] += o.events[l].wind[1]
+		o.events[l].merged = true
+		return true
+	}
+	return false
+}
+
+// collinearPieces reports whether the a and b pieces lie on the same line.
+func (o *polygonOverlay) collinearPieces(a, b int32) bool {
+	al := o.events[a].point
+	ar := o.events[o.events[a].other].point
+	bl := o.events[b].point
+	br := o.events[o.events[b].other].point
+	d := ar.Sub(al)
+	if math.Abs(d.Cross(bl.Sub(al))) > overlaySnapDist*d.Len() {
+		return false
+	}
+	return math.Abs(d.Cross(br.Sub(al))) <= overlaySnapDist*d.Len()
+}
+
+// pieceBelow reports whether the status piece s is below the piece e
+// that is being inserted into the status at its left point.
+func (o *polygonOverlay) pieceBelow(s, e int32) bool {
+	seg := &o.segments[o.events[s].segment]
+	sl, sr := seg[0], seg[1]
+	d := sr.Sub(sl)
+	if cross := d.Cross(o.events[e].point.Sub(sl)); math.Abs(cross) > overlaySnapDist*d.Len() {
+		return cross > 0
+	}
+	// The piece starts on the s line: compare the directions.
+	if k := orientation(sl, sr, o.events[o.events[e].other].point); k != 0 {
+		return k > 0
+	}
+	// The collinear pieces can go in any order, but it should be stable.
+	return s < e
+}
+
+func (o *polygonOverlay) pieceInsertIndex(e int32) int {
+	lo, hi := 0, len(o.status)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if o.pieceBelow(o.status[mid], e) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// pieceIndex returns the status index of the l piece that ends at p.
+func (o *polygonOverlay) pieceIndex(l int32, p Vec) int {
+	lo, hi := 0, len(o.status)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		seg := &o.segments[o.events[o.status[mid]].segment]
+		if orientation(seg[0], seg[1], p) > 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	// The pieces that pass through p are adjacent, l is one of them.
+	for i := lo; i < len(o.status); i++ {
+		s := o.status[i]
+		if s == l {
+			return i
+		}
+		seg := &o.segments[o.events[s].segment]
+		if orientation(seg[0], seg[1], p) < 0 {
+			break
+		}
+	}
+	// The round-off errors can break the status order a little;
+	// fall back to the linear search in this case.
+	for i, s := range o.status {
+		if s == l {
+			return i
+		}
+	}
+	return -1
+}
+
+func (o *polygonOverlay) intersectPieces(a, b int32) {
+	segA := &o.segments[o.events[a].segment]
+	segB := &o.segments[o.events[b].segment]
+	points, n := overlayIntersection(segA[0], segA[1], segB[0], segB[1])
+	switch n {
+	case 1:
+		// The segments cross at a single point, but it can be
+		// outside of the pieces (they're only parts of the segments).
+		p := overlayRound(points[0])
+		if !o.onPiece(a, p) || !o.onPiece(b, p) {
+			return
+		}
+		o.splitPiece(a, p)
+		o.splitPiece(b, p)
+	case 2:
+		// The collinear pieces are split at the ends of their common part.
+		points, n = overlayIntersection(
+			o.events[a].point, o.events[o.events[a].other].point,
+			o.events[b].point, o.events[o.events[b].other].point)
+		for i := 0; i < n; i++ {
+			p := overlayRound(points[i])
+			o.splitPiece(a, p)
+			o.splitPiece(b, p)
+		}
+	}
+}
+
+// onPiece reports whether p is on the l piece (within the snapping distance).
+func (o *polygonOverlay) onPiece(l int32, p Vec) bool {
+	q := closestPointOnSegment(o.events[l].point, o.events[o.events[l].other].point, p)
+	return q.DistanceSquaredTo(p) <= overlaySnapDist*overlaySnapDist
+}
+
+// splitPiece splits the l piece at p, unless p is one of its endpoints.
+// The left part remains in the status and the right part
+// is inserted into the status when the sweep line reaches p.
+func (o *polygonOverlay) splitPiece(l int32, p Vec) {
+	r := o.events[l].other
+	lp := o.events[l].point
+	rp := o.events[r].point
+	if !overlayPointLess(lp, p) || !overlayPointLess(p, rp) {
+		// The round-off errors can move the intersection point
+		// slightly out of the piece (e.g. to the left of a vertical piece);
+		// the closest point of the piece is used in this case.
+		q := overlayRound(closestPointOnSegment(lp, rp, p))
+		if q.DistanceSquaredTo(p) > overlaySnapDist*overlaySnapDist {
+			return
+		}
+		if !overlayPointLess(lp, q) || !overlayPointLess(q, rp) {
+			return
+		}
+		p = q
+	}
+	// The points that are that close would be merged anyway.
+	if lp.DistanceSquaredTo(p) <= overlaySnapDist*overlaySnapDist || rp.DistanceSquaredTo(p) <= overlaySnapDist*overlaySnapDist {
+		return
+	}
+	e := o.events[l]
+	newRight := o.newEvent(overlayEvent{point: p, other: l, segment: e.segment})
+	newLeft := o.newEvent(overlayEvent{point: p, other: r, left: true, segment: e.segment, wind: e.wind})
+	o.events[l].other = newRight
+	o.events[r].other = newLeft
+	o.pushEvent(newRight)
+	o.pushEvent(newLeft)
+}
+
+// overlayIntersection returns the points where the a1-a2 and b1-b2 segments should be split.
+// The segment endpoints are expected to be ordered by [overlayPointLess].
+//
+// For the collinear segments, both ends of their common part are returned,
+// the rightmost point goes first.
+func overlayIntersection(a1, a2, b1, b2 Vec) (points [2]Vec, n int) {
+	const eps = 1e-9
+
+	d1 := a2.Sub(a1)
+	d2 := b2.Sub(b1)
+	diff := b1.Sub(a1)
+	denom := d1.Cross(d2)
+	len1 := d1.Len()
+	len2 := d2.Len()
+
+	if math.Abs(denom) > eps*len1*len2 {
+		t := diff.Cross(d2) / denom
+		u := diff.Cross(d1) / denom
+		if t < -eps || t > 1+eps || u < -eps || u > 1+eps {
+			return points, 0
+		}
+		p := a1.Add(d1.Mulf(t))
+		// Prefer the existing points for the touching cases.
+		switch {
+		case t <= eps:
+			p = a1
+		case t >= 1-eps:
+			p = a2
+		case u <= eps:
+			p = b1
+		case u >= 1-eps:
+			p = b2
+		}
+		points[0] = p
+		return points, 1
+	}
+
+	// The segments are parallel.
+	// If they're collinear, their common part ends are the split points.
+	if math.Abs(diff.Cross(d1)) > overlaySnapDist*len1 {
+		return points, 0
+	}
+	start := a1
+	if overlayPointLess(a1, b1) {
+		start = b1
+	}
+	end := a2
+	if overlayPointLess(b2, a2) {
+		end = b2
+	}
+	if !overlayPointLess(start, end) {
+		return points, 0
+	}
+	// Splitting at the rightmost point first keeps the
+	// left part of the piece long enough for the second split.
+	points[0] = end
+	points[1] = start
+	return points, 2
+}
+
+// overlayRound snaps the point to a fine grid.
+// The same intersection point computed for the different segments
+// can differ in the last bits; the rounding makes these points identical
+// in most cases, so the sweep can rely on the exact comparisons.
+func overlayRound(p Vec) Vec {
+	const scale = 1 << 30
+	return Vec{
+		X: math.Round(p.X*scale) / scale,
+		Y: math.Round(p.Y*scale) / scale,
+	}
+}
+
+// overlayPointLess defines the sweep order: by X, then by Y.
+func overlayPointLess(a, b Vec) bool {
+	return a.X < b.X || (a.X == b.X && a.Y < b.Y)
+}
+
+// eventLess defines the event queue order: by the point,
+// then the right endpoints go before the left ones.
+func (o *polygonOverlay) eventLess(a, b int32) bool {
+	ea := &o.events[a]
+	eb := &o.events[b]
+	if ea.point != eb.point {
+		return overlayPointLess(ea.point, eb.point)
+	}
+	if ea.left != eb.left {
+		return eb.left
+	}
+	return a < b
+}
+
+func (o *polygonOverlay) pushEvent(id int32) {
+	o.queue = append(o.queue, id)
+	i := len(o.queue) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !o.eventLess(o.queue[i], o.queue[parent]) {
+			break
+		}
+		o.queue[i], o.queue[parent] = o.queue[parent], o.queue[i]
+		i = parent
+	}
+}
+
+func (o *polygonOverlay) popEvent() int32 {
+	id := o.queue[0]
+	n := len(o.queue) - 1
+	o.queue[0] = o.queue[n]
+	o.queue = o.queue[:n]
+	i := 0
+	for {
+		smallest := i
+		if l := 2*i + 1; l < n && o.eventLess(o.queue[l], o.queue[smallest]) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < n && o.eventLess(o.queue[r], o.queue[smallest]) {
+			smallest = r
+		}
+		if smallest == i {
+			return id
+		}
+		o.queue[i], o.queue[smallest] = o.queue[smallest], o.queue[i]
+		i = smallest
+	}
+}
+
+func (o *polygonOverlay) vertexID(p Vec) int {
+	cell := [2]int64{
+		int64(math.Floor(p.X / overlaySnapDist)),
+		int64(math.Floor(p.Y / overlaySnapDist)),
+	}
+	for dy := int64(-1); dy <= 1; dy++ {
+		for dx := int64(-1); dx <= 1; dx++ {
+			for _, id := range o.cells[[2]int64{cell[0] + dx, cell[1] + dy}] {
+				if o.vertices[id].DistanceSquaredTo(p) <= overlaySnapDist*overlaySnapDist {
+					return id
+				}
+			}
+		}
+	}
+	id := len(o.vertices)
+	o.vertices = append(o.vertices, p)
+	o.cells[cell] = append(o.cells[cell], id)
+	return id
+}
+
+func (o *polygonOverlay) buildEdges() {
+	for i := range o.events {
+		e := &o.events[i]
+		if !e.left || e.merged {
+			continue
+		}
+		from := o.vertexID(e.point)
+		to := o.vertexID(o.events[e.other].point)
+		o.addEdge(from, to, e.wind)
+	}
+}
+
+func (o *polygonOverlay) addEdge(from, to int, wind [2]int) {
+	if from == to {
+		return
+	}
+	key := [2]int{from, to}
+	delta := 1
+	if from > to {
+		key = [2]int{to, from}
+		delta = -1
+	}
+	index, ok := o.edgeByKey[key]
+	if !ok {
+		index = len(o.edges)
+		o.edgeByKey[key] = index
+		o.edges = append(o.edges, overlayEdge{a: key[0], b: key[1]})
+	}
+	o.edges[index].wind[0] += delta * wind[0]
+	o.edges[index].wind[1] += delta * wind[1]
+}
+
+// edgeEnds returns the left and right edge vertices and
+// the edge winding contributions for the left->right direction.
+func (o *polygonOverlay) edgeEnds(e int32) (left, right int, wind [2]int) {
+	edge := &o.edges[e]
+	if overlayPointLess(o.vertices[edge.b], o.vertices[edge.a]) {
+		return edge.b, edge.a, [2]int{-edge.wind[0], -edge.wind[1]}
+	}
+	return edge.a, edge.b, edge.wind
+}
+
+// selectEdges finds the edges that separate the inside and outside areas.
+// The selected edges are oriented in a way that the inside area
+// is to the right of them (for the screen coordinates).
+//
+// The edges don't cross each other, so the winding numbers below an edge
+// are equal to the winding numbers above its lower neighbor in the sweep line status.
+// A clockwise ring edge that goes from left to right increases
+// the winding number above it by 1.
+func (o *polygonOverlay) selectEdges(inside func(wind [2]int) bool) {
+	o.outgoing = make([][]int, len(o.vertices))
+
+	starting := make([][]int32, len(o.vertices))
+	numEnding := make([]int, len(o.vertices))
+	for i, e := range o.edges {
+		if e.wind == [2]int{} {
+			continue
+		}
+		left, right, _ := o.edgeEnds(int32(i))
+		starting[left] = append(starting[left], int32(i))
+		numEnding[right]++
+	}
+
+	order := make([]int32, len(o.vertices))
+	for i := range order {
+		order[i] = int32(i)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return overlayPointLess(o.vertices[order[i]], o.vertices[order[j]])
+	})
+
+	status := o.status[:0]
+	for _, v := range order {
+		p := o.vertices[v]
+
+		if numEnding[v] != 0 {
+			// The edges that end at p are adjacent in the status.
+			removed := 0
+			for i := o.edgeLowerBound(status, p); i < len(status) && removed < numEnding[v]; {
+				e := status[i]
+				if _, right, _ := o.edgeEnds(e); right == int(v) {
+					status = append(status[:i], status[i+1:]...)
+					removed++
+					continue
+				}
+				if o.edgeSide(e, p) < 0 {
+					break
+				}
+				i++
+			}
+			if removed != numEnding[v] {
+				// The round-off errors can break the status order a little;
+				// fall back to the linear search in this case.
+				filtered := status[:0]
+				for _, e := range status {
+					if _, right, _ := o.edgeEnds(e); right != int(v) {
+						filtered = append(filtered, e)
+					}
+				}
+				status = filtered
+			}
+		}
+
+		edges := starting[v]
+		if len(edges) == 0 {
+			continue
+		}
+		// Order the edges from the bottom to the top;
+		// a vertical edge goes last.
+		sort.Slice(edges, func(i, j int) bool {
+			_, ri, _ := o.edgeEnds(edges[i])
+			_, rj, _ := o.edgeEnds(edges[j])
+			return o.vertices[ri].Sub(p).Cross(o.vertices[rj].Sub(p)) > 0
+		})
+		i := o.edgeLowerBound(status, p)
+		_, firstRight, _ := o.edgeEnds(edges[0])
+		for i < len(status) && o.edgeSide(status[i], p) == 0 && o.edgeSide(status[i], o.vertices[firstRight]) > 0 {
+			i++
+		}
+		status = append(status, edges...)
+		copy(status[i+len(edges):], status[i:])
+		copy(status[i:], edges)
+
+		for k, e := range edges {
+			var below [2]int
+			if i+k > 0 {
+				below = o.edges[status[i+k-1]].above
+			}
+			left, right, wind := o.edgeEnds(e)
+			above := [2]int{below[0] + wind[0], below[1] + wind[1]}
+			o.edges[e].above = above
+
+			insideAbove := inside(above)
+			if inside(below) == insideAbove {
+				continue
+			}
+			from, to := left, right
+			if !insideAbove {
+				from, to = to, from
+			}
+			o.outgoing[from] = append(o.outgoing[from], len(o.directed))
+			o.directed = append(o.directed, overlayDirectedEdge{from: from, to: to})
+		}
+	}
+	o.status = status[:0]
+}
+
+// edgeSide returns 1 if p is above the edge line, -1 if it's below and 0 if it's on the line.
+//
+// Unlike orientation, it doesn't use the epsilon: after the vertices snapping,
+// the edges can be very short or almost vertical and the approximate
+// test would treat the points around them as collinear.
+func (o *polygonOverlay) edgeSide(e int32, p Vec) int {
+	left, right, _ := o.edgeEnds(e)
+	a := o.vertices[left]
+	b := o.vertices[right]
+	if p == a || p == b {
+		return 0
+	}
+	d := b.Sub(a)
+	q := p.Sub(a)
+	// The explicit conversions forbid the fused multiply-add.
+	cross := float64(d.X*q.Y) - float64(d.Y*q.X)
+	switch {
+	case cross > 0:
+		return 1
+	case cross < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// edgeLowerBound returns the index of the first status edge that is not below p.
+func (o *polygonOverlay) edgeLowerBound(status []int32, p Vec) int {
+	lo, hi := 0, len(status)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if o.edgeSide(status[mid], p) > 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+func (o *polygonOverlay) traceRings() []Polygon {
+	var rings []Polygon
+	o.usedEdges = make([]bool, len(o.directed))
+
+	for start := range o.directed {
+		if o.usedEdges[start] {
+			continue
+		}
+		ring := o.vertexTemp[:0]
+		e := start
+		for {
+			o.usedEdges[e] = true
+			edge := o.directed[e]
+			ring = append(ring, o.vertices[edge.from])
+			next := o.nextEdge(e, start)
+			if next == -1 || next == start {
+				break
+			}
+			e = next
+		}
+		o.vertexTemp = ring
+		if cleaned := cleanOverlayRing(ring); cleaned != nil {
+			rings = append(rings, cleaned)
+		}
+	}
+
+	return rings
+}
+
+// nextEdge selects the ring continuation after the e edge.
+// When there are several options, it picks the one that turns
+// towards the inside area the most; this keeps the touching rings separated.
+func (o *polygonOverlay) nextEdge(e, start int) int {
+	edge := o.directed[e]
+	dir := o.vertices[edge.to].Sub(o.vertices[edge.from])
+	best := -1
+	bestAngle := 0.0
+	for _, candidate := range o.outgoing[edge.to] {
+		if o.usedEdges[candidate] && candidate != start {
+			continue
+		}
+		c := o.directed[candidate]
+		cdir := o.vertices[c.to].Sub(o.vertices[c.from])
+		angle := math.Atan2(dir.Cross(cdir), dir.Dot(cdir))
+		if best == -1 || angle > bestAngle {
+			best = candidate
+			bestAngle = angle
+		}
+	}
+	return best
+}
+
+// cleanOverlayRing returns a copy of the ring without collinear vertices.
+// It returns nil for degenerate rings.
+func cleanOverlayRing(ring []Vec) Polygon {
+	result := make(Polygon, 0, len(ring))
+	for _, v := range ring {
+		result = append(result, v)
+		for len(result) >= 3 && orientation(result[len(result)-3], result[len(result)-2], result[len(result)-1]) == 0 {
+			result[len(result)-2] = result[len(result)-1]
+			result = result[:len(result)-1]
+		}
+	}
+	// Check the wrapping vertices as well.
+	for len(result) >= 3 {
+		n := len(result)
+		switch {
+		case orientation(result[n-2], result[n-1], result[0]) == 0:
+			result = result[:n-1]
+		case orientation(result[n-1], result[0], result[1]) == 0:
+			result = result[1:]
+		default:
+			if math.Abs(result.SignedArea()) < overlaySnapDist {
+				return nil
+			}
+			return result
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package gmath
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPolygonArea(t *testing.T) {
+	tests := []struct {
+		p          Polygon
+		signedArea float64
+		centroid   Vec
+		perimeter  float64
+	}{
+		{nil, 0, Vec{}, 0},
+		{Polygon{{1, 1}}, 0, Vec{1, 1}, 0},
+		{Polygon{{0, 0}, {2, 0}}, 0, Vec{1, 0}, 4},
+		{Polygon{{0, 0}, {1, 0}, {2, 0}}, 0, Vec{1, 0}, 4},
+
+		{Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}}, 4, Vec{1, 1}, 8},
+		{Polygon{{0, 2}, {2, 2}, {2, 0}, {0, 0}}, -4, Vec{1, 1}, 8},
+		{Polygon{{10, 10}, {14, 10}, {14, 13}}, 6, Vec{12.666666666, 11}, 12},
+		{Polygon{{0, 0}, {4, 0}, {4, 1}, {1, 1}, {1, 4}, {0, 4}}, 7, Vec{1.357142857, 1.357142857}, 16},
+	}
+
+	for _, test := range tests {
+		if have := test.p.SignedArea(); !EqualApprox(have, test.signedArea) {
+			t.Fatalf("SignedArea(%v):\nhave: %v\nwant: %v", test.p, have, test.signedArea)
+		}
+		if have := test.p.Reversed().SignedArea(); !EqualApprox(have, -test.signedArea) {
+			t.Fatalf("SignedArea(Reversed(%v)):\nhave: %v\nwant: %v", test.p, have, -test.signedArea)
+		}
+		if have := test.p.IsClockwise(); have != (test.signedArea > 0) {
+			t.Fatalf("IsClockwise(%v):\nhave: %v\nwant: %v", test.p, have, !have)
+		}
+		if have := test.p.Centroid(); !have.EqualApprox(test.centroid) {
+			t.Fatalf("Centroid(%v):\nhave: %v\nwant: %v", test.p, have, test.centroid)
+		}
+		if have := test.p.Perimeter(); !EqualApprox(have, test.perimeter) {
+			t.Fatalf("Perimeter(%v):\nhave: %v\nwant: %v", test.p, have, test.perimeter)
+		}
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	square := Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	lshape := Polygon{{0, 0}, {4, 0}, {4, 1}, {1, 1}, {1, 4}, {0, 4}}
+	// A pentagram: its center is covered twice.
+	star := Polygon{{50, 0}, {79, 90}, {2, 35}, {97, 35}, {21, 90}}
+
+	tests := []struct {
+		p       Polygon
+		point   Vec
+		evenOdd bool
+		nonZero bool
+	}{
+		{square, Vec{5, 5}, true, true},
+		{square, Vec{1, 9}, true, true},
+		{square, Vec{-1, 5}, false, false},
+		{square, Vec{11, 5}, false, false},
+		{square, Vec{5, 11}, false, false},
+
+		{lshape, Vec{0.5, 3}, true, true},
+		{lshape, Vec{3, 0.5}, true, true},
+		{lshape, Vec{3, 3}, false, false},
+
+		{star, Vec{50, 50}, false, true},
+		{star, Vec{50, 10}, true, true},
+		{star, Vec{5, 5}, false, false},
+	}
+
+	for _, test := range tests {
+		if have := test.p.Contains(test.point); have != test.evenOdd {
+			t.Fatalf("Contains(%v, %v):\nhave: %v\nwant: %v", test.p, test.point, have, test.evenOdd)
+		}
+		if have := test.p.ContainsWithRule(test.point, FillNonZero); have != test.nonZero {
+			t.Fatalf("ContainsWithRule(%v, %v, nonzero):\nhave: %v\nwant: %v", test.p, test.point, have, test.nonZero)
+		}
+		reversed := test.p.Reversed()
+		if have := reversed.ContainsWithRule(test.point, FillNonZero); have != test.nonZero {
+			t.Fatalf("ContainsWithRule(%v, %v, nonzero):\nhave: %v\nwant: %v", reversed, test.point, have, test.nonZero)
+		}
+	}
+}
+
+func TestPolygonShapeChecks(t *testing.T) {
+	tests := []struct {
+		p      Polygon
+		convex bool
+		simple bool
+	}{
+		{Polygon{}, false, false},
+		{Polygon{{0, 0}, {1, 1}}, false, false},
+		{Polygon{{0, 0}, {1, 0}, {2, 0}}, false, false},
+
+		{Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, true, true},
+		{Polygon{{0, 10}, {10, 10}, {10, 0}, {0, 0}}, true, true},
+		{Polygon{{0, 0}, {5, 0}, {10, 0}, {10, 10}, {0, 10}}, true, true},
+		{Polygon{{0, 0}, {4, 0}, {4, 1}, {1, 1}, {1, 4}, {0, 4}}, false, true},
+
+		// Bow-tie.
+		{Polygon{{0, 0}, {10, 10}, {10, 0}, {0, 10}}, false, false},
+		// Pentagram: all turns are in the same direction.
+		{Polygon{{50, 0}, {79, 90}, {2, 35}, {97, 35}, {21, 90}}, false, false},
+		// A spike that goes back along the same line.
+		{Polygon{{0, 0}, {10, 0}, {5, 0}, {5, 5}}, false, false},
+		// Two vertices touch each other.
+		{Polygon{{0, 0}, {4, 0}, {2, 2}, {4, 4}, {0, 4}, {2, 2}}, false, false},
+	}
+
+	for _, test := range tests {
+		if have := test.p.IsConvex(); have != test.convex {
+			t.Fatalf("IsConvex(%v):\nhave: %v\nwant: %v", test.p, have, test.convex)
+		}
+		if have := test.p.IsSimple(); have != test.simple {
+			t.Fatalf("IsSimple(%v):\nhave: %v\nwant: %v", test.p, have, test.simple)
+		}
+	}
+}
+
+func TestPolygonBounds(t *testing.T) {
+	p := Polygon{{3, -1}, {10, 4}, {-2, 7}}
+	want := Rect{Min: Vec{-2, -1}, Max: Vec{10, 7}}
+	if have := p.Bounds(); have != want {
+		t.Fatalf("Bounds(%v):\nhave: %v\nwant: %v", p, have, want)
+	}
+}
+
+func TestPolygonJSON(t *testing.T) {
+	tests := []struct {
+		p    Polygon
+		want string
+	}{
+		{Polygon{}, `[]`},
+		{Polygon{{}}, `[[]]`},
+		{Polygon{{1, 2}}, `[[1,2]]`},
+		{Polygon{{1, 2}, {0, 0}, {-1.5, 3.25}}, `[[1,2],[],[-1.5,3.25]]`},
+	}
+
+	for _, test := range tests {
+		data, err := json.Marshal(test.p)
+		if err != nil {
+			t.Fatalf("marshal %v: %v", test.p, err)
+		}
+		if string(data) != test.want {
+			t.Fatalf("marshal %v:\nhave: %s\nwant: %s", test.p, data, test.want)
+		}
+		var p Polygon
+		if err := json.Unmarshal(data, &p); err != nil {
+			t.Fatalf("unmarshal %s: %v", data, err)
+		}
+		if len(p) != len(test.p) {
+			t.Fatalf("unmarshal %s: length mismatch", data)
+		}
+		for i := range p {
+			if p[i] != test.p[i] {
+				t.Fatalf("unmarshal %s:\nhave: %v\nwant: %v", data, p, test.p)
+			}
+		}
+	}
+
+	var p Polygon
+	if err := json.Unmarshal([]byte(" [ [1, 2] , [ 3,4 ] ] "), &p); err != nil {
+		t.Fatal(err)
+	}
+	if len(p) != 2 || p[0] != (Vec{1, 2}) || p[1] != (Vec{3, 4}) {
+		t.Fatalf("unexpected unmarshal result: %v", p)
+	}
+
+	badInputs := []string{
+		`[[1,2],]`,
+		`[[1,2] [3,4]]`,
+		`[[1,2]`,
+		`[[1 2]]`,
+	}
+	for _, s := range badInputs {
+		var p Polygon
+		if err := json.Unmarshal([]byte(s), &p); err == nil {
+			t.Fatalf("unmarshal %s: expected an error", s)
+		}
+	}
+}
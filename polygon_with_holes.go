@@ -0,0 +1,85 @@
+package gmath
+
+// PolygonWithHoles is a polygon that can have holes inside of it.
+//
+// The operations that produce this type (like boolean operations
+// and offsetting) use a clockwise winding for the outer polygon and
+// a counter-clockwise winding for the holes.
+type PolygonWithHoles struct {
+	Outer Polygon
+	Holes []Polygon
+}
+
+// Area returns the outer polygon area minus the area of its holes.
+func (p PolygonWithHoles) Area() float64 {
+	area := p.Outer.Area()
+	for _, h := range p.Holes {
+		area -= h.Area()
+	}
+	return area
+}
+
+// Contains reports whether the point is inside the outer polygon,
+// but not inside any of its holes.
+func (p PolygonWithHoles) Contains(point Vec) bool {
+	if !p.Outer.Contains(point) {
+		return false
+	}
+	for _, h := range p.Holes {
+		if h.Contains(point) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bounds returns the bounding rectangle of the outer polygon.
+func (p PolygonWithHoles) Bounds() Rect {
+	return p.Outer.Bounds()
+}
+
+// assembleRegions groups the outer rings (clockwise)
+// and holes (counter-clockwise) together.
+func assembleRegions(rings []Polygon) []PolygonWithHoles {
+	var result []PolygonWithHoles
+	var outerArea []float64
+	var holes []Polygon
+	for _, ring := range rings {
+		if ring.IsClockwise() {
+			result = append(result, PolygonWithHoles{Outer: ring})
+			outerArea = append(outerArea, ring.Area())
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+
+	for _, h := range holes {
+		// A middle of the longest edge is a good sample point:
+		// it can't lie on the outer polygon boundary.
+		var sample Vec
+		maxLen := -1.0
+		prev := h[len(h)-1]
+		for _, v := range h {
+			if l := prev.DistanceSquaredTo(v); l > maxLen {
+				maxLen = l
+				sample = prev.Midpoint(v)
+			}
+			prev = v
+		}
+
+		best := -1
+		for i := range result {
+			if best != -1 && outerArea[i] >= outerArea[best] {
+				continue
+			}
+			if result[i].Outer.Contains(sample) {
+				best = i
+			}
+		}
+		if best != -1 {
+			result[best].Holes = append(result[best].Holes, h)
+		}
+	}
+
+	return result
+}
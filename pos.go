@@ -1,35 +1,61 @@
 package gmath
 
 // Pos represents a position with optional offset relative to its base.
+//
+// If Rotation is not nil, the offset is rotated by that angle during the resolving.
+// This is useful for the offsets that should follow the owner orientation,
+// like a turret barrel or a weapon muzzle.
 type Pos struct {
-	Base   *Vec
-	Offset Vec
+	Base     *Vec
+	Offset   Vec
+	Rotation *Rad
 }
 
 func MakePos(base Vec) Pos {
 	return Pos{Base: &base}
 }
 
+// MakeRotatedPos creates a position that rotates its offset by the referenced angle.
+func MakeRotatedPos(base *Vec, rotation *Rad, offset Vec) Pos {
+	return Pos{Base: base, Offset: offset, Rotation: rotation}
+}
+
 func (p Pos) Resolve() Vec {
+	offset := p.Offset
+	if p.Rotation != nil {
+		offset = offset.Rotated(*p.Rotation)
+	}
 	if p.Base == nil {
-		return p.Offset
+		return offset
 	}
-	return p.Base.Add(p.Offset)
+	return p.Base.Add(offset)
 }
 
 func (p *Pos) SetBase(base Vec) {
 	p.Base = &base
 }
 
+// Set changes the base and the offset.
+// The rotation binding is preserved.
 func (p *Pos) Set(base *Vec, offsetX, offsetY float64) {
 	p.Base = base
 	p.Offset.X = offsetX
 	p.Offset.Y = offsetY
 }
 
+// WithOffset returns a copy of the position with the offset increased by the given values.
+// The rotation binding is preserved; the added offset is rotated along with the original one.
 func (p Pos) WithOffset(offsetX, offsetY float64) Pos {
 	return Pos{
-		Base:   p.Base,
-		Offset: Vec{X: p.Offset.X + offsetX, Y: p.Offset.Y + offsetY},
+		Base:     p.Base,
+		Offset:   Vec{X: p.Offset.X + offsetX, Y: p.Offset.Y + offsetY},
+		Rotation: p.Rotation,
 	}
 }
+
+// WithRotation returns a copy of the position bound to the given rotation.
+// A nil rotation removes the binding.
+func (p Pos) WithRotation(rotation *Rad) Pos {
+	p.Rotation = rotation
+	return p
+}
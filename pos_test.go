@@ -0,0 +1,42 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPosResolve(t *testing.T) {
+	base := Vec{10, 20}
+	p := Pos{Base: &base, Offset: Vec{5, 0}}
+	if have := p.Resolve(); have != (Vec{15, 20}) {
+		t.Fatalf("Resolve(): %v", have)
+	}
+
+	rotation := Rad(math.Pi / 2)
+	muzzle := MakeRotatedPos(&base, &rotation, Vec{5, 0})
+	if have := muzzle.Resolve(); !have.EqualApprox(Vec{10, 25}) {
+		t.Fatalf("rotated Resolve(): %v", have)
+	}
+
+	// The offset follows the owner rotation changes.
+	rotation = math.Pi
+	if have := muzzle.Resolve(); !have.EqualApprox(Vec{5, 20}) {
+		t.Fatalf("rotated Resolve() after rotation change: %v", have)
+	}
+
+	tip := muzzle.WithOffset(5, 1)
+	if tip.Rotation != &rotation {
+		t.Fatal("WithOffset lost the rotation binding")
+	}
+	if have := tip.Resolve(); !have.EqualApprox(Vec{0, 19}) {
+		t.Fatalf("WithOffset Resolve(): %v", have)
+	}
+
+	if have := tip.WithRotation(nil).Resolve(); !have.EqualApprox(Vec{20, 21}) {
+		t.Fatalf("WithRotation(nil) Resolve(): %v", have)
+	}
+
+	if have := (Pos{Offset: Vec{1, 0}, Rotation: &rotation}).Resolve(); !have.EqualApprox(Vec{-1, 0}) {
+		t.Fatalf("nil base rotated Resolve(): %v", have)
+	}
+}
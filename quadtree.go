@@ -0,0 +1,417 @@
+package gmath
+
+import (
+	"math"
+)
+
+// QuadtreeConfig describes the [Quadtree] construction parameters.
+type QuadtreeConfig struct {
+	// Capacity is a max number of items a leaf node can hold before it's split.
+	// A zero value means 8.
+	Capacity int
+
+	// MaxDepth is a max depth of the tree; the nodes at this depth are never split.
+	// A zero value means 8.
+	MaxDepth int
+}
+
+// Quadtree is a spatial index that recursively divides
+// its area into four quadrants when they have too many items.
+//
+// The items are stored in the deepest node that fully contains their bounds.
+// The items that are outside of the tree bounds are kept in the root node:
+// they're handled correctly, but less efficiently.
+//
+// Like with [SpatialHash], the queries append the results to the provided slice.
+type Quadtree[T comparable] struct {
+	capacity int
+	maxDepth int
+
+	nodes []quadtreeNode[T]
+	index map[T]int32
+
+	// freeNodes contains the first indices of the unused children blocks.
+	freeNodes []int32
+}
+
+type quadtreeNode[T comparable] struct {
+	bounds Rect
+	items  []quadtreeEntry[T]
+
+	parent int32
+	depth  int32
+
+	// children is an index of the first child node (all 4 of them are stored sequentially).
+	// It's -1 for the leaf nodes.
+	children int32
+}
+
+type quadtreeEntry[T comparable] struct {
+	item   T
+	bounds Rect
+}
+
+// NewQuadtree creates an empty tree that covers the specified area.
+func NewQuadtree[T comparable](bounds Rect, config QuadtreeConfig) *Quadtree[T] {
+	if config.Capacity <= 0 {
+		config.Capacity = 8
+	}
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = 8
+	}
+	t := &Quadtree[T]{
+		capacity: config.Capacity,
+		maxDepth: config.MaxDepth,
+		index:    make(map[T]int32),
+	}
+	t.nodes = append(t.nodes, quadtreeNode[T]{
+		bounds:   bounds,
+		parent:   -1,
+		children: -1,
+	})
+	return t
+}
+
+// Bounds returns the area covered by the tree.
+func (t *Quadtree[T]) Bounds() Rect { return t.nodes[0].bounds }
+
+// Len returns the number of items inside the tree.
+func (t *Quadtree[T]) Len() int { return len(t.index) }
+
+// Insert adds the item with the given bounds.
+// If the item is already inside the tree, it's moved instead.
+func (t *Quadtree[T]) Insert(item T, bounds Rect) {
+	if _, ok := t.index[item]; ok {
+		t.Move(item, bounds)
+		return
+	}
+	t.insert(quadtreeEntry[T]{item: item, bounds: bounds})
+}
+
+// Remove deletes the item from the tree.
+// It returns false if there was no such item.
+func (t *Quadtree[T]) Remove(item T) bool {
+	nodeIndex, ok := t.index[item]
+	if !ok {
+		return false
+	}
+	t.removeFromNode(nodeIndex, item)
+	delete(t.index, item)
+	t.tryMerge(nodeIndex)
+	return true
+}
+
+// Move changes the item bounds.
+// It returns false if there was no such item.
+func (t *Quadtree[T]) Move(item T, bounds Rect) bool {
+	nodeIndex, ok := t.index[item]
+	if !ok {
+		return false
+	}
+
+	// A fast path: the item still belongs to the same node.
+	node := &t.nodes[nodeIndex]
+	if nodeIndex == 0 || rectEncloses(node.bounds, bounds) {
+		if node.children == -1 || t.childFor(node, bounds) == -1 {
+			for i := range node.items {
+				if node.items[i].item == item {
+					node.items[i].bounds = bounds
+					break
+				}
+			}
+			return true
+		}
+	}
+
+	t.removeFromNode(nodeIndex, item)
+	t.tryMerge(nodeIndex)
+	t.insert(quadtreeEntry[T]{item: item, bounds: bounds})
+	return true
+}
+
+// QueryRect appends all items that overlap the area to dst.
+// The touching items are included.
+func (t *Quadtree[T]) QueryRect(dst []T, area Rect) []T {
+	return t.queryRect(dst, 0, area)
+}
+
+// QueryCircle is like [Quadtree.QueryRect], but the area is a circle.
+func (t *Quadtree[T]) QueryCircle(dst []T, area Circle) []T {
+	return t.queryCircle(dst, 0, area)
+}
+
+// Nearest finds the item that is the closest to the given point.
+// The distance is measured to the item bounds, so
+// for the point inside of the item bounds it's 0.
+//
+// It returns false if the tree is empty.
+func (t *Quadtree[T]) Nearest(p Vec) (T, bool) {
+	var result T
+	bestDist := math.Inf(1)
+	found := t.nearest(0, p, &result, &bestDist)
+	return result, found
+}
+
+// WalkNodes calls the visit function for every tree node.
+// It's intended for debugging and visualization.
+//
+// The nodes are visited in the depth-first order, parents before children.
+func (t *Quadtree[T]) WalkNodes(visit func(bounds Rect, depth int, numItems int)) {
+	t.walkNodes(0, visit)
+}
+
+func (t *Quadtree[T]) walkNodes(nodeIndex int32, visit func(bounds Rect, depth int, numItems int)) {
+	node := &t.nodes[nodeIndex]
+	visit(node.bounds, int(node.depth), len(node.items))
+	if node.children != -1 {
+		for i := int32(0); i < 4; i++ {
+			t.walkNodes(node.children+i, visit)
+		}
+	}
+}
+
+func (t *Quadtree[T]) insert(e quadtreeEntry[T]) {
+	nodeIndex := int32(0)
+	for {
+		node := &t.nodes[nodeIndex]
+		if node.children == -1 {
+			break
+		}
+		child := t.childFor(node, e.bounds)
+		if child == -1 {
+			break
+		}
+		if nodeIndex == 0 && !rectEncloses(node.bounds, e.bounds) {
+			break
+		}
+		nodeIndex = child
+	}
+
+	node := &t.nodes[nodeIndex]
+	node.items = append(node.items, e)
+	t.index[e.item] = nodeIndex
+	if node.children == -1 && len(node.items) > t.capacity && int(node.depth) < t.maxDepth {
+		t.split(nodeIndex)
+	}
+}
+
+func (t *Quadtree[T]) split(nodeIndex int32) {
+	children := t.allocChildren()
+	node := &t.nodes[nodeIndex]
+	node.children = children
+	bounds := node.bounds
+	center := bounds.Center()
+	quadrants := [4]Rect{
+		{Min: bounds.Min, Max: center},
+		{Min: Vec{X: center.X, Y: bounds.Min.Y}, Max: Vec{X: bounds.Max.X, Y: center.Y}},
+		{Min: Vec{X: bounds.Min.X, Y: center.Y}, Max: Vec{X: center.X, Y: bounds.Max.Y}},
+		{Min: center, Max: bounds.Max},
+	}
+	for i, q := range quadrants {
+		child := &t.nodes[children+int32(i)]
+		child.bounds = q
+		child.parent = nodeIndex
+		child.depth = node.depth + 1
+		child.children = -1
+	}
+
+	// Move the items that fit into the children.
+	items := node.items
+	kept := items[:0]
+	for _, e := range items {
+		child := int32(-1)
+		if nodeIndex != 0 || rectEncloses(bounds, e.bounds) {
+			child = t.childFor(node, e.bounds)
+		}
+		if child == -1 {
+			kept = append(kept, e)
+			continue
+		}
+		t.nodes[child].items = append(t.nodes[child].items, e)
+		t.index[e.item] = child
+	}
+	var zero quadtreeEntry[T]
+	for i := len(kept); i < len(items); i++ {
+		items[i] = zero
+	}
+	node.items = kept
+
+	for i := int32(0); i < 4; i++ {
+		child := &t.nodes[children+i]
+		if len(child.items) > t.capacity && int(child.depth) < t.maxDepth {
+			t.split(children + i)
+		}
+	}
+}
+
+// childFor returns the node child index that fully contains the bounds.
+// It returns -1 if the bounds don't fit into any of the children.
+func (t *Quadtree[T]) childFor(node *quadtreeNode[T], bounds Rect) int32 {
+	center := node.bounds.Center()
+	var offset int32
+	switch {
+	case bounds.Max.X <= center.X:
+	case bounds.Min.X >= center.X:
+		offset++
+	default:
+		return -1
+	}
+	switch {
+	case bounds.Max.Y <= center.Y:
+	case bounds.Min.Y >= center.Y:
+		offset += 2
+	default:
+		return -1
+	}
+	return node.children + offset
+}
+
+func (t *Quadtree[T]) allocChildren() int32 {
+	if len(t.freeNodes) != 0 {
+		children := t.freeNodes[len(t.freeNodes)-1]
+		t.freeNodes = t.freeNodes[:len(t.freeNodes)-1]
+		return children
+	}
+	children := int32(len(t.nodes))
+	for i := 0; i < 4; i++ {
+		t.nodes = append(t.nodes, quadtreeNode[T]{})
+	}
+	return children
+}
+
+func (t *Quadtree[T]) removeFromNode(nodeIndex int32, item T) {
+	node := &t.nodes[nodeIndex]
+	for i := range node.items {
+		if node.items[i].item == item {
+			last := len(node.items) - 1
+			node.items[i] = node.items[last]
+			node.items[last] = quadtreeEntry[T]{}
+			node.items = node.items[:last]
+			return
+		}
+	}
+}
+
+// tryMerge collapses the children of the node parent if
+// they don't have enough items anymore.
+// The check continues up to the root.
+func (t *Quadtree[T]) tryMerge(nodeIndex int32) {
+	if t.nodes[nodeIndex].children == -1 {
+		nodeIndex = t.nodes[nodeIndex].parent
+	}
+	for nodeIndex != -1 {
+		node := &t.nodes[nodeIndex]
+		total := len(node.items)
+		for i := int32(0); i < 4; i++ {
+			child := &t.nodes[node.children+i]
+			if child.children != -1 {
+				return
+			}
+			total += len(child.items)
+		}
+		if total > t.capacity {
+			return
+		}
+		for i := int32(0); i < 4; i++ {
+			child := &t.nodes[node.children+i]
+			for i, e := range child.items {
+				node.items = append(node.items, e)
+				t.index[e.item] = nodeIndex
+				child.items[i] = quadtreeEntry[T]{}
+			}
+			// Keep the items capacity for the reuse.
+			child.items = child.items[:0]
+		}
+		t.freeNodes = append(t.freeNodes, node.children)
+		node.children = -1
+		nodeIndex = node.parent
+	}
+}
+
+func (t *Quadtree[T]) queryRect(dst []T, nodeIndex int32, area Rect) []T {
+	node := &t.nodes[nodeIndex]
+	for i := range node.items {
+		e := &node.items[i]
+		if rectsTouch(e.bounds, area) {
+			dst = append(dst, e.item)
+		}
+	}
+	if node.children != -1 {
+		for i := int32(0); i < 4; i++ {
+			if rectsTouch(t.nodes[node.children+i].bounds, area) {
+				dst = t.queryRect(dst, node.children+i, area)
+			}
+		}
+	}
+	return dst
+}
+
+func (t *Quadtree[T]) queryCircle(dst []T, nodeIndex int32, area Circle) []T {
+	node := &t.nodes[nodeIndex]
+	r2 := area.Radius * area.Radius
+	for i := range node.items {
+		e := &node.items[i]
+		if e.bounds.ClosestPoint(area.Center).DistanceSquaredTo(area.Center) <= r2 {
+			dst = append(dst, e.item)
+		}
+	}
+	if node.children != -1 {
+		for i := int32(0); i < 4; i++ {
+			child := &t.nodes[node.children+i]
+			if child.bounds.ClosestPoint(area.Center).DistanceSquaredTo(area.Center) <= r2 {
+				dst = t.queryCircle(dst, node.children+i, area)
+			}
+		}
+	}
+	return dst
+}
+
+func (t *Quadtree[T]) nearest(nodeIndex int32, p Vec, result *T, bestDist *float64) bool {
+	found := false
+	node := &t.nodes[nodeIndex]
+	for i := range node.items {
+		e := &node.items[i]
+		if d := e.bounds.ClosestPoint(p).DistanceSquaredTo(p); d < *bestDist {
+			*bestDist = d
+			*result = e.item
+			found = true
+		}
+	}
+	if node.children == -1 {
+		return found
+	}
+
+	// Visit the closer children first: it makes the pruning more efficient.
+	var order [4]int32
+	var dist [4]float64
+	for i := int32(0); i < 4; i++ {
+		order[i] = node.children + i
+		dist[i] = t.nodes[order[i]].bounds.ClosestPoint(p).DistanceSquaredTo(p)
+		for j := i; j > 0 && dist[j] < dist[j-1]; j-- {
+			dist[j], dist[j-1] = dist[j-1], dist[j]
+			order[j], order[j-1] = order[j-1], order[j]
+		}
+	}
+	for i, child := range order {
+		if dist[i] >= *bestDist {
+			break
+		}
+		if t.nearest(child, p, result, bestDist) {
+			found = true
+		}
+	}
+	return found
+}
+
+// rectsTouch is like [Rect.Intersects], but the touching rects are reported too.
+func rectsTouch(a, b Rect) bool {
+	return a.Min.X <= b.Max.X && b.Min.X <= a.Max.X &&
+		a.Min.Y <= b.Max.Y && b.Min.Y <= a.Max.Y
+}
+
+// rectEncloses is like [Rect.ContainsRect], but the empty inner rects
+// are not special-cased: their position matters.
+func rectEncloses(outer, inner Rect) bool {
+	return outer.Min.X <= inner.Min.X && inner.Max.X <= outer.Max.X &&
+		outer.Min.Y <= inner.Min.Y && inner.Max.Y <= outer.Max.Y
+}
@@ -0,0 +1,170 @@
+package gmath
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQuadtreeRandomized(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := NewQuadtree[int](Rect{Max: Vec{256, 256}}, QuadtreeConfig{Capacity: 4, MaxDepth: 6})
+
+	items := make(map[int]Rect)
+	randomRect := func() Rect {
+		// Some of the items are outside of the tree bounds.
+		pos := Vec{rng.Float64()*300 - 20, rng.Float64()*300 - 20}
+		if rng.Intn(5) == 0 {
+			return Rect{Min: pos, Max: pos}
+		}
+		return Rect{Min: pos, Max: pos.Add(Vec{rng.Float64() * 20, rng.Float64() * 20})}
+	}
+
+	var buf []int
+	for round := 0; round < 50; round++ {
+		for j := 0; j < 20; j++ {
+			id := rng.Intn(150)
+			switch rng.Intn(4) {
+			case 0:
+				_, exists := items[id]
+				delete(items, id)
+				if have := tree.Remove(id); have != exists {
+					t.Fatalf("round %d: Remove(%d) returned %v", round, id, have)
+				}
+			case 1:
+				if _, ok := items[id]; ok {
+					r := items[id].Add(Vec{rng.Float64()*10 - 5, rng.Float64()*10 - 5})
+					items[id] = r
+					if !tree.Move(id, r) {
+						t.Fatalf("round %d: Move(%d) failed", round, id)
+					}
+					continue
+				}
+				fallthrough
+			default:
+				r := randomRect()
+				items[id] = r
+				tree.Insert(id, r)
+			}
+		}
+		if tree.Len() != len(items) {
+			t.Fatalf("round %d: Len()=%d, want %d", round, tree.Len(), len(items))
+		}
+
+		area := randomRect()
+		area.Max = area.Max.Add(Vec{30, 30})
+		buf = tree.QueryRect(buf[:0], area)
+		want := 0
+		for _, r := range items {
+			if rectsTouch(r, area) {
+				want++
+			}
+		}
+		if len(buf) != want {
+			t.Fatalf("round %d: QueryRect found %d items, want %d", round, len(buf), want)
+		}
+
+		c := Circle{Center: Vec{rng.Float64() * 256, rng.Float64() * 256}, Radius: rng.Float64() * 50}
+		buf = tree.QueryCircle(buf[:0], c)
+		want = 0
+		for _, r := range items {
+			if r.ClosestPoint(c.Center).DistanceTo(c.Center) <= c.Radius {
+				want++
+			}
+		}
+		if len(buf) != want {
+			t.Fatalf("round %d: QueryCircle found %d items, want %d", round, len(buf), want)
+		}
+
+		p := Vec{rng.Float64()*300 - 20, rng.Float64()*300 - 20}
+		nearest, ok := tree.Nearest(p)
+		if ok != (len(items) != 0) {
+			t.Fatalf("round %d: Nearest returned %v", round, ok)
+		}
+		bestDist := math.Inf(1)
+		for _, r := range items {
+			bestDist = math.Min(bestDist, r.ClosestPoint(p).DistanceTo(p))
+		}
+		if ok && !EqualApprox(items[nearest].ClosestPoint(p).DistanceTo(p), bestDist) {
+			t.Fatalf("round %d: Nearest(%v) found %d at %v, want %v", round, p, nearest, items[nearest], bestDist)
+		}
+
+		numItems := 0
+		tree.WalkNodes(func(bounds Rect, depth int, n int) {
+			if depth > 6 {
+				t.Fatalf("round %d: node depth %d exceeds the limit", round, depth)
+			}
+			numItems += n
+		})
+		if numItems != len(items) {
+			t.Fatalf("round %d: nodes contain %d items, want %d", round, numItems, len(items))
+		}
+	}
+
+	for id := range items {
+		tree.Remove(id)
+	}
+	numNodes := 0
+	tree.WalkNodes(func(bounds Rect, depth int, n int) {
+		numNodes++
+	})
+	if numNodes != 1 {
+		t.Fatalf("empty tree has %d nodes", numNodes)
+	}
+	if _, ok := tree.Nearest(Vec{}); ok {
+		t.Fatal("Nearest for the empty tree returned true")
+	}
+}
+
+func TestQuadtreeSplit(t *testing.T) {
+	tree := NewQuadtree[int](Rect{Max: Vec{100, 100}}, QuadtreeConfig{Capacity: 2})
+	tree.Insert(1, Rect{Min: Vec{10, 10}, Max: Vec{20, 20}})
+	tree.Insert(2, Rect{Min: Vec{60, 10}, Max: Vec{70, 20}})
+	tree.Insert(3, Rect{Min: Vec{10, 60}, Max: Vec{20, 70}})
+	// Crosses the center lines, so it's kept in the root.
+	tree.Insert(4, Rect{Min: Vec{45, 45}, Max: Vec{55, 55}})
+
+	type nodeInfo struct {
+		bounds   Rect
+		depth    int
+		numItems int
+	}
+	var nodes []nodeInfo
+	tree.WalkNodes(func(bounds Rect, depth int, numItems int) {
+		nodes = append(nodes, nodeInfo{bounds, depth, numItems})
+	})
+	want := []nodeInfo{
+		{Rect{Max: Vec{100, 100}}, 0, 1},
+		{Rect{Max: Vec{50, 50}}, 1, 1},
+		{Rect{Min: Vec{50, 0}, Max: Vec{100, 50}}, 1, 1},
+		{Rect{Min: Vec{0, 50}, Max: Vec{50, 100}}, 1, 1},
+		{Rect{Min: Vec{50, 50}, Max: Vec{100, 100}}, 1, 0},
+	}
+	if len(nodes) != len(want) {
+		t.Fatalf("nodes:\nhave: %v\nwant: %v", nodes, want)
+	}
+	for i := range nodes {
+		if nodes[i] != want[i] {
+			t.Fatalf("nodes[%d]:\nhave: %v\nwant: %v", i, nodes[i], want[i])
+		}
+	}
+
+	tree.Remove(4)
+	tree.Remove(3)
+	nodes = nodes[:0]
+	tree.WalkNodes(func(bounds Rect, depth int, numItems int) {
+		nodes = append(nodes, nodeInfo{bounds, depth, numItems})
+	})
+	if len(nodes) != 1 || nodes[0].numItems != 2 {
+		t.Fatalf("nodes after merge: %v", nodes)
+	}
+	// The freed nodes should not keep the references to the items.
+	for i := 1; i < len(tree.nodes); i++ {
+		items := tree.nodes[i].items
+		for _, e := range items[:cap(items)] {
+			if e != (quadtreeEntry[int]{}) {
+				t.Fatalf("node %d keeps a merged entry %v", i, e)
+			}
+		}
+	}
+}
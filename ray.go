@@ -0,0 +1,88 @@
+package gmath
+
+import (
+	"math"
+)
+
+// RayHit describes a point where a ray hits a shape.
+type RayHit struct {
+	// Pos is the hit point in the world space.
+	Pos Vec
+
+	// Normal is a normalized surface normal at the hit point.
+	// It points outside of the shape.
+	Normal Vec
+
+	// Distance is a distance from the ray origin to the hit point.
+	Distance float64
+}
+
+// rayCircle finds the first hit point of a ray with a circle.
+// The dir is expected to be normalized.
+// The rays that start inside the circle are not reported.
+func rayCircle(origin, dir, center Vec, r float64) (float64, bool) {
+	m := origin.Sub(center)
+	b := m.Dot(dir)
+	c := m.LenSquared() - r*r
+	if c > 0 && b > 0 {
+		// The ray starts outside and points away.
+		return 0, false
+	}
+	discr := b*b - c
+	if discr < 0 {
+		return 0, false
+	}
+	t := -b - math.Sqrt(discr)
+	if t < 0 {
+		return 0, false
+	}
+	return t, true
+}
+
+// raySegment finds the hit point of a ray with the a-b segment.
+// The collinear cases are not reported.
+func raySegment(origin, dir, a, b Vec) (float64, bool) {
+	ab := b.Sub(a)
+	denom := dir.Cross(ab)
+	if denom == 0 {
+		return 0, false
+	}
+	ao := a.Sub(origin)
+	t := ao.Cross(ab) / denom
+	u := ao.Cross(dir) / denom
+	if t < 0 || u < 0 || u > 1 {
+		return 0, false
+	}
+	return t, true
+}
+
+// rayRect finds the first hit point of a ray with a rectangle using the slab method.
+// The dir doesn't need to be normalized; the result is measured in dir lengths.
+// If the ray starts inside the rectangle, 0 is returned.
+func rayRect(origin, dir Vec, r Rect) (float64, bool) {
+	tmin := 0.0
+	tmax := math.Inf(1)
+	for axis := 0; axis < 2; axis++ {
+		o, d, lo, hi := origin.X, dir.X, r.Min.X, r.Max.X
+		if axis == 1 {
+			o, d, lo, hi = origin.Y, dir.Y, r.Min.Y, r.Max.Y
+		}
+		if d == 0 {
+			if o < lo || o > hi {
+				return 0, false
+			}
+			continue
+		}
+		t1 := (lo - o) / d
+		t2 := (hi - o) / d
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tmin = math.Max(tmin, t1)
+		tmax = math.Min(tmax, t2)
+		if tmin > tmax {
+			return 0, false
+		}
+	}
+	return tmin, true
+}
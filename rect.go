@@ -55,6 +55,17 @@ func (r Rect) Center() Vec {
 	}
 }
 
+// Corners returns the rectangle vertices in the clockwise order,
+// starting from the top-left one (Min).
+func (r Rect) Corners() [4]Vec {
+	return [4]Vec{
+		r.Min,
+		{X: r.Max.X, Y: r.Min.Y},
+		r.Max,
+		{X: r.Min.X, Y: r.Max.Y},
+	}
+}
+
 func (r Rect) X1() float64 { return r.Min.X }
 
 func (r Rect) Y1() float64 { return r.Min.Y }
@@ -87,6 +98,40 @@ func (r Rect) Intersects(other Rect) bool {
 		r.Min.Y < other.Max.Y && other.Min.Y < r.Max.Y
 }
 
+// Bounds returns the rectangle itself.
+// It's needed to implement the [Shape] interface.
+func (r Rect) Bounds() Rect { return r }
+
+// ClosestPoint returns the point of the rectangle that is closest to p.
+// For a point that is inside the rectangle, the point itself is returned.
+//
+// Unlike [Rect.Contains], the Max border is considered to be a part of the rectangle.
+func (r Rect) ClosestPoint(p Vec) Vec {
+	return Vec{
+		X: Clamp(p.X, r.Min.X, r.Max.X),
+		Y: Clamp(p.Y, r.Min.Y, r.Max.Y),
+	}
+}
+
+// Translate moves the rectangle by the given offset.
+// It's like [Rect.Add], but it modifies the rectangle in place.
+func (r *Rect) Translate(offset Vec) {
+	*r = r.Add(offset)
+}
+
+// Support returns the rectangle corner that is the farthest in the given direction.
+// It implements the [Supporter] interface.
+func (r Rect) Support(dir Vec) Vec {
+	p := r.Min
+	if dir.X > 0 {
+		p.X = r.Max.X
+	}
+	if dir.Y > 0 {
+		p.Y = r.Max.Y
+	}
+	return p
+}
+
 func (r Rect) Add(p Vec) Rect {
 	return Rect{
 		Min: r.Min.Add(p),
@@ -0,0 +1,196 @@
+package gmath
+
+import (
+	"math"
+)
+
+// Penetration describes how two overlapping shapes should be separated.
+//
+// Moving the second shape by Normal*Depth (or the first one by -Normal*Depth)
+// resolves the overlap; this is also known as a minimum translation vector.
+type Penetration struct {
+	// Normal is a normalized direction that points from the first shape to the second one.
+	Normal Vec
+
+	// Depth is a positive overlap distance along the Normal.
+	Depth float64
+}
+
+// Vec returns the minimum translation vector: Normal*Depth.
+func (p Penetration) Vec() Vec {
+	return p.Normal.Mulf(p.Depth)
+}
+
+// SATPolygons checks two convex polygons for overlapping using
+// the separating axis theorem.
+// It returns the penetration info and true if they overlap.
+// Like with [Rect.Intersects], the touching shapes do not overlap.
+//
+// The polygons can use any winding order.
+// The results are undefined for non-convex polygons.
+//
+// When several axes have the same penetration depth,
+// the first one is selected: the edges of a are checked before the edges of b,
+// and the edges are checked in the order of their vertices.
+func SATPolygons(a, b Polygon) (Penetration, bool) {
+	if len(a) < 3 || len(b) < 3 {
+		return Penetration{}, false
+	}
+	var result Penetration
+	result.Depth = math.Inf(1)
+	if !satPolygonAxes(&result, a, a, b) || !satPolygonAxes(&result, b, a, b) {
+		return Penetration{}, false
+	}
+	return result, true
+}
+
+// SATPolygonCircle is like [SATPolygons], but the second shape is a circle.
+func SATPolygonCircle(a Polygon, b Circle) (Penetration, bool) {
+	if len(a) < 3 {
+		return Penetration{}, false
+	}
+	var result Penetration
+	result.Depth = math.Inf(1)
+
+	for i, v := range a {
+		next := a[(i+1)%len(a)]
+		axis := edgeNormal(v, next)
+		if axis.IsZero() {
+			continue
+		}
+		minA, maxA := projectPolygon(a, axis)
+		c := b.Center.Dot(axis)
+		if !satUpdate(&result, axis, minA, maxA, c-b.Radius, c+b.Radius) {
+			return Penetration{}, false
+		}
+	}
+
+	// The circle can only be separated from a vertex
+	// by the axis that goes through the closest vertex.
+	closest := a[0]
+	for _, v := range a[1:] {
+		if v.DistanceSquaredTo(b.Center) < closest.DistanceSquaredTo(b.Center) {
+			closest = v
+		}
+	}
+	if axis := b.Center.Sub(closest).Normalized(); !axis.IsZero() {
+		minA, maxA := projectPolygon(a, axis)
+		c := b.Center.Dot(axis)
+		if !satUpdate(&result, axis, minA, maxA, c-b.Radius, c+b.Radius) {
+			return Penetration{}, false
+		}
+	}
+
+	return result, true
+}
+
+// SATCircles is like [SATPolygons], but for two circles.
+//
+// If both circles have the same center, the normal is {1, 0}.
+func SATCircles(a, b Circle) (Penetration, bool) {
+	d := b.Center.Sub(a.Center)
+	r := a.Radius + b.Radius
+	distSqr := d.LenSquared()
+	if distSqr >= r*r {
+		return Penetration{}, false
+	}
+	dist := math.Sqrt(distSqr)
+	if dist == 0 {
+		return Penetration{Normal: Vec{X: 1}, Depth: r}, true
+	}
+	return Penetration{Normal: d.Divf(dist), Depth: r - dist}, true
+}
+
+// SATRects is like [SATPolygons], but for two axis-aligned rectangles.
+// The X axis is preferred over the Y axis if penetration depths are equal.
+func SATRects(a, b Rect) (Penetration, bool) {
+	if !a.Intersects(b) {
+		return Penetration{}, false
+	}
+	var result Penetration
+	result.Depth = math.Inf(1)
+	satUpdate(&result, Vec{X: 1}, a.Min.X, a.Max.X, b.Min.X, b.Max.X)
+	satUpdate(&result, Vec{Y: 1}, a.Min.Y, a.Max.Y, b.Min.Y, b.Max.Y)
+	return result, true
+}
+
+// SATRectCircle is like [SATPolygons], but for a rectangle and a circle.
+func SATRectCircle(a Rect, b Circle) (Penetration, bool) {
+	if a.IsEmpty() {
+		return Penetration{}, false
+	}
+	corners := a.Corners()
+	return SATPolygonCircle(corners[:], b)
+}
+
+// SATOBBs is like [SATPolygons], but for two oriented boxes.
+// Use [OBBFromRect] to test an [OBB] against a [Rect].
+func SATOBBs(a, b OBB) (Penetration, bool) {
+	cornersA := a.Corners()
+	cornersB := b.Corners()
+	return SATPolygons(cornersA[:], cornersB[:])
+}
+
+// SATOBBCircle is like [SATPolygons], but for an oriented box and a circle.
+func SATOBBCircle(a OBB, b Circle) (Penetration, bool) {
+	corners := a.Corners()
+	return SATPolygonCircle(corners[:], b)
+}
+
+func satPolygonAxes(result *Penetration, axesSource, a, b Polygon) bool {
+	for i, v := range axesSource {
+		next := axesSource[(i+1)%len(axesSource)]
+		axis := edgeNormal(v, next)
+		if axis.IsZero() {
+			continue
+		}
+		minA, maxA := projectPolygon(a, axis)
+		minB, maxB := projectPolygon(b, axis)
+		if !satUpdate(result, axis, minA, maxA, minB, maxB) {
+			return false
+		}
+	}
+	return true
+}
+
+// satUpdate checks the projections overlap along the axis.
+// If they overlap less than the current result depth, the result is updated.
+// It returns false if the axis separates the shapes.
+func satUpdate(result *Penetration, axis Vec, minA, maxA, minB, maxB float64) bool {
+	forward := maxA - minB  // Moving b along the axis
+	backward := maxB - minA // Moving b against the axis
+	if forward <= 0 || backward <= 0 {
+		return false
+	}
+	if forward <= backward {
+		if forward < result.Depth {
+			result.Depth = forward
+			result.Normal = axis
+		}
+	} else {
+		if backward < result.Depth {
+			result.Depth = backward
+			result.Normal = axis.Neg()
+		}
+	}
+	return true
+}
+
+func projectPolygon(p Polygon, axis Vec) (min, max float64) {
+	min = p[0].Dot(axis)
+	max = min
+	for _, v := range p[1:] {
+		proj := v.Dot(axis)
+		if proj < min {
+			min = proj
+		} else if proj > max {
+			max = proj
+		}
+	}
+	return min, max
+}
+
+func edgeNormal(a, b Vec) Vec {
+	d := b.Sub(a)
+	return Vec{X: -d.Y, Y: d.X}.Normalized()
+}
@@ -0,0 +1,149 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSATPolygons(t *testing.T) {
+	square := Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	translate := func(p Polygon, offset Vec) Polygon {
+		result := make(Polygon, len(p))
+		for i, v := range p {
+			result[i] = v.Add(offset)
+		}
+		return result
+	}
+
+	tests := []struct {
+		a       Polygon
+		b       Polygon
+		overlap bool
+		normal  Vec
+		depth   float64
+	}{
+		{square, translate(square, Vec{20, 0}), false, Vec{}, 0},
+		{square, translate(square, Vec{10, 0}), false, Vec{}, 0},
+		{square, translate(square, Vec{8, 1}), true, Vec{1, 0}, 2},
+		{square, translate(square, Vec{-8, 1}), true, Vec{-1, 0}, 2},
+		{square, translate(square, Vec{1, 7}), true, Vec{0, 1}, 3},
+		{square, translate(square, Vec{1, -7}), true, Vec{0, -1}, 3},
+		{square, square.Reversed(), true, Vec{0, 1}, 10},
+		{square, translate(square, Vec{5, 5}), true, Vec{0, 1}, 5},
+		{square, Polygon{{12, 5}, {17, 0}, {17, 10}}, false, Vec{}, 0},
+		{square, Polygon{{9, 5}, {14, 0}, {14, 10}}, true, Vec{1, 0}, 1},
+		{square, Polygon{{16, 5}, {5, 16}, {5, 20}, {20, 5}}, false, Vec{}, 0},
+		{square, Polygon{{9, 5}, {5, 9}, {5, 15}, {15, 5}}, true, Vec{math.Sqrt2 / 2, math.Sqrt2 / 2}, 3 * math.Sqrt2},
+	}
+
+	for _, test := range tests {
+		have, ok := SATPolygons(test.a, test.b)
+		if ok != test.overlap {
+			t.Fatalf("SATPolygons(%v, %v):\nhave: %v\nwant: %v", test.a, test.b, ok, test.overlap)
+		}
+		if !ok {
+			continue
+		}
+		if !have.Normal.EqualApprox(test.normal) || !EqualApprox(have.Depth, test.depth) {
+			t.Fatalf("SATPolygons(%v, %v):\nhave: %v %v\nwant: %v %v", test.a, test.b, have.Normal, have.Depth, test.normal, test.depth)
+		}
+		// Applying the MTV must resolve the overlap.
+		if _, ok := SATPolygons(test.a, translate(test.b, have.Vec().Mulf(1+Epsilon))); ok {
+			t.Fatalf("SATPolygons(%v, %v): MTV did not resolve the overlap", test.a, test.b)
+		}
+	}
+}
+
+func TestSATPolygonCircle(t *testing.T) {
+	square := Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+
+	tests := []struct {
+		c       Circle
+		overlap bool
+		normal  Vec
+		depth   float64
+	}{
+		{Circle{Center: Vec{15, 5}, Radius: 5}, false, Vec{}, 0},
+		{Circle{Center: Vec{14, 5}, Radius: 5}, true, Vec{1, 0}, 1},
+		{Circle{Center: Vec{5, -3}, Radius: 4}, true, Vec{0, -1}, 1},
+		{Circle{Center: Vec{13, 14}, Radius: 4.9}, false, Vec{}, 0},
+		{Circle{Center: Vec{13, 14}, Radius: 6}, true, Vec{0.6, 0.8}, 1},
+		{Circle{Center: Vec{2, 5}, Radius: 1}, true, Vec{-1, 0}, 3},
+	}
+
+	for _, test := range tests {
+		have, ok := SATPolygonCircle(square, test.c)
+		if ok != test.overlap {
+			t.Fatalf("SATPolygonCircle(%v):\nhave: %v\nwant: %v", test.c, ok, test.overlap)
+		}
+		if !ok {
+			continue
+		}
+		if !have.Normal.EqualApprox(test.normal) || !EqualApprox(have.Depth, test.depth) {
+			t.Fatalf("SATPolygonCircle(%v):\nhave: %v %v\nwant: %v %v", test.c, have.Normal, have.Depth, test.normal, test.depth)
+		}
+
+		rectResult, ok := SATRectCircle(Rect{Max: Vec{10, 10}}, test.c)
+		if !ok || rectResult != have {
+			t.Fatalf("SATRectCircle(%v) mismatch: %v", test.c, rectResult)
+		}
+	}
+}
+
+func TestSATCircles(t *testing.T) {
+	tests := []struct {
+		a       Circle
+		b       Circle
+		overlap bool
+		normal  Vec
+		depth   float64
+	}{
+		{Circle{Vec{0, 0}, 1}, Circle{Vec{2, 0}, 1}, false, Vec{}, 0},
+		{Circle{Vec{0, 0}, 1}, Circle{Vec{1.5, 0}, 1}, true, Vec{1, 0}, 0.5},
+		{Circle{Vec{0, 0}, 2}, Circle{Vec{0, -3}, 2}, true, Vec{0, -1}, 1},
+		{Circle{Vec{1, 1}, 1}, Circle{Vec{1, 1}, 1}, true, Vec{1, 0}, 2},
+	}
+	for _, test := range tests {
+		have, ok := SATCircles(test.a, test.b)
+		if ok != test.overlap {
+			t.Fatalf("SATCircles(%v, %v):\nhave: %v\nwant: %v", test.a, test.b, ok, test.overlap)
+		}
+		if ok && (!have.Normal.EqualApprox(test.normal) || !EqualApprox(have.Depth, test.depth)) {
+			t.Fatalf("SATCircles(%v, %v):\nhave: %v %v\nwant: %v %v", test.a, test.b, have.Normal, have.Depth, test.normal, test.depth)
+		}
+	}
+}
+
+func TestSATBoxes(t *testing.T) {
+	a := Rect{Max: Vec{10, 10}}
+	b := Rect{Min: Vec{7, 8}, Max: Vec{20, 20}}
+	have, ok := SATRects(a, b)
+	if !ok || have.Normal != (Vec{0, 1}) || have.Depth != 2 {
+		t.Fatalf("SATRects: unexpected result %v %v", have, ok)
+	}
+	have2, ok := SATOBBs(OBBFromRect(a), OBBFromRect(b))
+	if !ok || !have2.Normal.EqualApprox(have.Normal) || !EqualApprox(have2.Depth, have.Depth) {
+		t.Fatalf("SATOBBs: unexpected result %v %v", have2, ok)
+	}
+	if _, ok := SATRects(a, Rect{Min: Vec{10, 0}, Max: Vec{20, 10}}); ok {
+		t.Fatalf("SATRects: touching rects overlap")
+	}
+
+	// Equal depths: X axis is preferred.
+	have, _ = SATRects(a, Rect{Min: Vec{8, 8}, Max: Vec{20, 20}})
+	if have.Normal != (Vec{1, 0}) {
+		t.Fatalf("SATRects: unexpected tie break: %v", have.Normal)
+	}
+
+	diamond := OBB{Center: Vec{0, 0}, HalfExtents: Vec{1, 1}, Rotation: math.Pi / 4}
+	box := OBB{Center: Vec{2, 0}, HalfExtents: Vec{1, 1}}
+	have, ok = SATOBBs(diamond, box)
+	if !ok || !have.Normal.EqualApprox(Vec{1, 0}) || !EqualApprox(have.Depth, math.Sqrt2-1) {
+		t.Fatalf("SATOBBs: unexpected result %v %v", have, ok)
+	}
+
+	have, ok = SATOBBCircle(diamond, Circle{Center: Vec{2, 0}, Radius: 1})
+	if !ok || !have.Normal.EqualApprox(Vec{1, 0}) || !EqualApprox(have.Depth, math.Sqrt2-1) {
+		t.Fatalf("SATOBBCircle: unexpected result %v %v", have, ok)
+	}
+}
@@ -0,0 +1,152 @@
+package gmath
+
+// Shape is a common interface for the 2D shapes.
+//
+// Implemented by [*Rect], [*Circle], [*Capsule], [*OBB] and [Polygon].
+// The pointer receivers are needed for Translate that modifies the shape in place.
+type Shape interface {
+	// Bounds returns the smallest axis-aligned rectangle that contains the shape.
+	Bounds() Rect
+
+	// Contains reports whether the point is inside the shape.
+	Contains(p Vec) bool
+
+	// ClosestPoint returns the point of the shape that is closest to p.
+	// For a point that is inside the shape, the point itself is returned.
+	ClosestPoint(p Vec) Vec
+
+	// Translate moves the shape by the given offset.
+	Translate(offset Vec)
+}
+
+// ShapesOverlap reports whether two shapes overlap.
+//
+// The known shape pairs are tested with the specialized functions
+// like [Rect.Intersects] and [Capsule.IntersectsCircle]; the touching
+// shapes do not overlap in this case.
+//
+// The concave polygons are split into triangles (see [Triangulate]) that are
+// tested one by one, so the result is exact, but it's more expensive than
+// testing a convex polygon. The polygons with less than 3 vertices never overlap.
+//
+// The other shapes are tested with [GJKIntersects] if they implement [Supporter].
+// If they don't, only their bounds are tested.
+func ShapesOverlap(a, b Shape) bool {
+	// A *Polygon is handled just like a Polygon.
+	if p, ok := a.(*Polygon); ok {
+		a = *p
+	}
+	if p, ok := b.(*Polygon); ok {
+		b = *p
+	}
+
+	if p, ok := a.(Polygon); ok {
+		if len(p) < 3 {
+			return false
+		}
+		if !p.IsConvex() {
+			return concavePolygonOverlaps(p, b)
+		}
+	}
+	if p, ok := b.(Polygon); ok {
+		if len(p) < 3 {
+			return false
+		}
+		if !p.IsConvex() {
+			return concavePolygonOverlaps(p, a)
+		}
+	}
+
+	if result, ok := shapesOverlap(a, b); ok {
+		return result
+	}
+	if result, ok := shapesOverlap(b, a); ok {
+		return result
+	}
+	supporterA, ok1 := a.(Supporter)
+	supporterB, ok2 := b.(Supporter)
+	if ok1 && ok2 {
+		return GJKIntersects(supporterA, supporterB)
+	}
+	return a.Bounds().Intersects(b.Bounds())
+}
+
+func concavePolygonOverlaps(p Polygon, other Shape) bool {
+	indices := Triangulate(p)
+	triangle := make(Polygon, 3)
+	for i := 0; i+2 < len(indices); i += 3 {
+		triangle[0] = p[indices[i]]
+		triangle[1] = p[indices[i+1]]
+		triangle[2] = p[indices[i+2]]
+		if orientation(triangle[0], triangle[1], triangle[2]) == 0 {
+			continue
+		}
+		if ShapesOverlap(triangle, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// shapesOverlap handles the known shape pairs.
+// The second result is false if the pair is not supported in this order.
+func shapesOverlap(a, b Shape) (bool, bool) {
+	switch a := a.(type) {
+	case *Rect:
+		switch b := b.(type) {
+		case *Rect:
+			return a.Intersects(*b), true
+		case *Circle:
+			return b.IntersectsRect(*a), true
+		case *Capsule:
+			return b.IntersectsRect(*a), true
+		case *OBB:
+			return b.IntersectsRect(*a), true
+		case Polygon:
+			if a.IsEmpty() {
+				return false, true
+			}
+			corners := a.Corners()
+			_, ok := SATPolygons(corners[:], b)
+			return ok, true
+		}
+
+	case *Circle:
+		switch b := b.(type) {
+		case *Circle:
+			return a.IntersectsCircle(*b), true
+		case *Capsule:
+			return b.IntersectsCircle(*a), true
+		case *OBB:
+			return b.IntersectsCircle(*a), true
+		case Polygon:
+			_, ok := SATPolygonCircle(b, *a)
+			return ok, true
+		}
+
+	case *Capsule:
+		switch b := b.(type) {
+		case *Capsule:
+			return a.IntersectsCapsule(*b), true
+		}
+
+	case *OBB:
+		switch b := b.(type) {
+		case *OBB:
+			return a.IntersectsOBB(*b), true
+		case Polygon:
+			corners := a.Corners()
+			_, ok := SATPolygons(corners[:], b)
+			return ok, true
+		}
+
+	case Polygon:
+		switch b := b.(type) {
+		case Polygon:
+			_, ok := SATPolygons(a, b)
+			return ok, true
+		}
+	}
+
+	return false, false
+}
@@ -0,0 +1,92 @@
+package gmath
+
+import (
+	"testing"
+)
+
+func TestShapesOverlap(t *testing.T) {
+	tests := []struct {
+		a    Shape
+		b    Shape
+		want bool
+	}{
+		{&Rect{Max: Vec{10, 10}}, &Rect{Min: Vec{5, 5}, Max: Vec{15, 15}}, true},
+		{&Rect{Max: Vec{10, 10}}, &Rect{Min: Vec{10, 0}, Max: Vec{15, 15}}, false},
+		{&Rect{Max: Vec{10, 10}}, &Circle{Center: Vec{12, 5}, Radius: 2}, false},
+		{&Rect{Max: Vec{10, 10}}, &Circle{Center: Vec{12, 5}, Radius: 2.5}, true},
+		{&Circle{Center: Vec{0, 0}, Radius: 1}, &Circle{Center: Vec{2, 0}, Radius: 1}, false},
+		{&Circle{Center: Vec{0, 0}, Radius: 1}, &Circle{Center: Vec{1.5, 0}, Radius: 1}, true},
+		{&Capsule{A: Vec{0, 0}, B: Vec{10, 10}, Radius: 1}, &Rect{Min: Vec{6, 0}, Max: Vec{10, 5.5}}, true},
+		{&Capsule{A: Vec{0, 0}, B: Vec{10, 10}, Radius: 1}, &Circle{Center: Vec{10, 0}, Radius: 1}, false},
+		{&Capsule{A: Vec{0, 0}, B: Vec{10, 10}, Radius: 1}, &Capsule{A: Vec{0, 10}, B: Vec{10, 0}, Radius: 0.1}, true},
+		{&Capsule{A: Vec{0, 0}, B: Vec{10, 0}, Radius: 1}, &OBB{Center: Vec{5, 3}, HalfExtents: Vec{1, 1}}, false},
+		{&Capsule{A: Vec{0, 0}, B: Vec{10, 0}, Radius: 1}, &OBB{Center: Vec{5, 1.8}, HalfExtents: Vec{1, 1}, Rotation: 0.3}, true},
+		{&OBB{HalfExtents: Vec{1, 1}}, &Rect{Min: Vec{0.5, 0.5}, Max: Vec{2, 2}}, true},
+		{Polygon{{0, 0}, {10, 0}, {5, 10}}, &Circle{Center: Vec{5, 3}, Radius: 1}, true},
+		{Polygon{{0, 0}, {10, 0}, {5, 10}}, &Rect{Min: Vec{8, 8}, Max: Vec{12, 12}}, false},
+		{Polygon{{0, 0}, {10, 0}, {5, 10}}, Polygon{{6, 6}, {12, 8}, {10, 12}}, true},
+		{Polygon{{0, 0}, {10, 0}, {5, 10}}, &Capsule{A: Vec{10, 10}, B: Vec{20, 10}, Radius: 1}, false},
+		{Polygon{{0, 0}, {10, 0}, {5, 10}}, &Capsule{A: Vec{5, 5}, B: Vec{20, 10}, Radius: 1}, true},
+		{Polygon{}, &Capsule{A: Vec{0, 0}, B: Vec{10, 0}, Radius: 1}, false},
+		{Polygon{{0, 0}, {10, 0}}, &Circle{Center: Vec{5, 0}, Radius: 1}, false},
+		{Polygon{}, Polygon{{0, 0}, {10, 0}, {5, 10}}, false},
+		// A U-shaped concave polygon: the notch is between x=4 and x=6.
+		{Polygon{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 2}, {4, 2}, {4, 10}, {0, 10}}, &Circle{Center: Vec{5, 7}, Radius: 0.5}, false},
+		{Polygon{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 2}, {4, 2}, {4, 10}, {0, 10}}, &Circle{Center: Vec{5, 7}, Radius: 1.5}, true},
+		{Polygon{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 2}, {4, 2}, {4, 10}, {0, 10}}, &Rect{Min: Vec{4.5, 3}, Max: Vec{5.5, 12}}, false},
+		{Polygon{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 2}, {4, 2}, {4, 10}, {0, 10}}, &Rect{Min: Vec{4.5, 1}, Max: Vec{5.5, 12}}, true},
+		{Polygon{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 2}, {4, 2}, {4, 10}, {0, 10}}, Polygon{{4.5, 3}, {5.5, 3}, {5, 9}}, false},
+		{&Polygon{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 2}, {4, 2}, {4, 10}, {0, 10}}, &Circle{Center: Vec{5, 7}, Radius: 0.5}, false},
+		{&Polygon{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 2}, {4, 2}, {4, 10}, {0, 10}}, &Polygon{{4.5, 3}, {5.5, 3}, {5, 9}}, false},
+		{&Polygon{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 2}, {4, 2}, {4, 10}, {0, 10}}, &Circle{Center: Vec{5, 7}, Radius: 1.5}, true},
+		{&Polygon{}, &Circle{Center: Vec{0, 0}, Radius: 1}, false},
+	}
+
+	for _, test := range tests {
+		if have := ShapesOverlap(test.a, test.b); have != test.want {
+			t.Fatalf("ShapesOverlap(%v, %v):\nhave: %v\nwant: %v", test.a, test.b, have, test.want)
+		}
+		if have := ShapesOverlap(test.b, test.a); have != test.want {
+			t.Fatalf("ShapesOverlap(%v, %v) reversed:\nhave: %v\nwant: %v", test.b, test.a, have, test.want)
+		}
+	}
+}
+
+func TestShapeTranslate(t *testing.T) {
+	shapes := []Shape{
+		&Rect{Min: Vec{1, 1}, Max: Vec{3, 4}},
+		&Circle{Center: Vec{2, 2}, Radius: 1},
+		&Capsule{A: Vec{1, 1}, B: Vec{3, 3}, Radius: 0.5},
+		&OBB{Center: Vec{2, 2}, HalfExtents: Vec{1, 0.5}, Rotation: 0.5},
+		Polygon{{1, 1}, {3, 1}, {2, 3}},
+	}
+
+	offset := Vec{10, -5}
+	for _, s := range shapes {
+		bounds := s.Bounds()
+		inside := bounds.Center()
+		if !s.Contains(inside) {
+			t.Fatalf("%v: Contains(%v) is false", s, inside)
+		}
+		if have := s.ClosestPoint(inside); have != inside {
+			t.Fatalf("%v: ClosestPoint(%v) for inner point: %v", s, inside, have)
+		}
+		outside := bounds.Max.Add(Vec{1, 1})
+		if s.Contains(outside) {
+			t.Fatalf("%v: Contains(%v) is true", s, outside)
+		}
+		if closest := s.ClosestPoint(outside); closest == outside || closest.DistanceTo(outside) < 1 {
+			t.Fatalf("%v: ClosestPoint(%v) for outer point: %v", s, outside, closest)
+		}
+
+		s.Translate(offset)
+		have := s.Bounds()
+		want := bounds.Add(offset)
+		if !have.Min.EqualApprox(want.Min) || !have.Max.EqualApprox(want.Max) {
+			t.Fatalf("%v: translated bounds:\nhave: %v\nwant: %v", s, have, want)
+		}
+		if !s.Contains(inside.Add(offset)) {
+			t.Fatalf("%v: Contains(%v) after Translate is false", s, inside.Add(offset))
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package gmath
+
+import (
+	"container/heap"
+	"math"
+)
+
+// SimplifyRDP reduces the number of points in a polyline
+// using the Ramer-Douglas-Peucker algorithm.
+//
+// The tolerance is the maximum allowed distance between
+// the original points and the simplified polyline.
+//
+// The first and the last points are always preserved.
+// The result is a new slice, points are not modified.
+func SimplifyRDP(points []Vec, tolerance float64) []Vec {
+	if len(points) <= 2 {
+		return append([]Vec(nil), points...)
+	}
+	keep := make([]bool, len(points))
+	markRDP(points, keep, 0, len(points)-1, tolerance)
+	return collectKept(points, keep)
+}
+
+// SimplifyVW reduces the number of points in a polyline
+// using the Visvalingam-Whyatt algorithm.
+//
+// The points are removed in the order of their significance
+// (the area of a triangle formed by a point and its neighbors)
+// until every remaining point has an effective area of at least minArea.
+//
+// The first and the last points are always preserved.
+// The result is a new slice, points are not modified.
+func SimplifyVW(points []Vec, minArea float64) []Vec {
+	return simplifyVW(points, false, minArea, 0)
+}
+
+// SimplifyVWCount is like [SimplifyVW], but it removes the points
+// until there are no more than count points left.
+func SimplifyVWCount(points []Vec, count int) []Vec {
+	return simplifyVW(points, false, math.Inf(1), count)
+}
+
+// SimplifyRDP is a closed polygon version of [SimplifyRDP] function.
+//
+// If p is simple, the result is guaranteed to be simple as well.
+// The winding order is preserved.
+// If the tolerance is too high to satisfy these requirements,
+// a smaller tolerance is used instead.
+func (p Polygon) SimplifyRDP(tolerance float64) Polygon {
+	if len(p) <= 3 {
+		return p.Clone()
+	}
+
+	simple := p.IsSimple()
+	sign := fsign(p.SignedArea())
+	keep := make([]bool, len(p))
+	result := make(Polygon, 0, len(p))
+
+	// A polygon is split into two chains: from the first vertex
+	// to the farthest one and then back to the first vertex.
+	farthest := 0
+	maxDist := 0.0
+	for i := 1; i < len(p); i++ {
+		if dist := p[0].DistanceSquaredTo(p[i]); dist > maxDist {
+			maxDist = dist
+			farthest = i
+		}
+	}
+	if farthest == 0 {
+		return p.Clone()
+	}
+
+	// Shrink the tolerance until the result is good enough.
+	// 32 halvings make any practical tolerance effectively zero.
+	for attempt := 0; attempt < 32; attempt++ {
+		for i := range keep {
+			keep[i] = false
+		}
+		markRDP(p, keep, 0, farthest, tolerance)
+		markRDPWrapped(p, keep, farthest, tolerance)
+
+		result = result[:0]
+		for i, v := range p {
+			if keep[i] {
+				result = append(result, v)
+			}
+		}
+		if len(result) >= 3 && fsign(result.SignedArea()) == sign && (!simple || result.IsSimple()) {
+			return result
+		}
+		tolerance *= 0.5
+	}
+
+	return p.Clone()
+}
+
+// SimplifyVW is a closed polygon version of [SimplifyVW] function.
+//
+// The points that would introduce a self-intersection are never removed,
+// so a simple polygon remains simple. The winding order is preserved.
+// The result always has at least 3 vertices.
+func (p Polygon) SimplifyVW(minArea float64) Polygon {
+	return simplifyVW(p, true, minArea, 0)
+}
+
+// SimplifyVWCount is like [Polygon.SimplifyVW], but it removes the points
+// until there are no more than count points left.
+//
+// It's possible for the result to contain more than count points
+// if the removal would cause a self-intersection.
+func (p Polygon) SimplifyVWCount(count int) Polygon {
+	return simplifyVW(p, true, math.Inf(1), count)
+}
+
+// markRDP marks the significant points of the [from, to] range.
+// The endpoints are always marked.
+func markRDP(points []Vec, keep []bool, from, to int, tolerance float64) {
+	keep[from] = true
+	keep[to] = true
+
+	type span struct{ from, to int }
+	stack := []span{{from, to}}
+	toleranceSqr := tolerance * tolerance
+	for len(stack) != 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		maxDist := 0.0
+		index := -1
+		a := points[s.from]
+		b := points[s.to]
+		for i := s.from + 1; i < s.to; i++ {
+			dist := closestPointOnSegment(a, b, points[i]).DistanceSquaredTo(points[i])
+			if dist > maxDist {
+				maxDist = dist
+				index = i
+			}
+		}
+		if index == -1 || maxDist <= toleranceSqr {
+			continue
+		}
+		keep[index] = true
+		stack = append(stack, span{s.from, index}, span{index, s.to})
+	}
+}
+
+// markRDPWrapped is like markRDP, but it handles a polygon chain
+// that goes from the given vertex to the end and then wraps to the first vertex.
+func markRDPWrapped(points []Vec, keep []bool, from int, tolerance float64) {
+	// Instead of complicating the markRDP indexing,
+	// run it over a temporary chain copy.
+	chain := make([]Vec, 0, len(points)-from+1)
+	chain = append(chain, points[from:]...)
+	chain = append(chain, points[0])
+	chainKeep := make([]bool, len(chain))
+	markRDP(chain, chainKeep, 0, len(chain)-1, tolerance)
+	for i := range chainKeep[:len(chainKeep)-1] {
+		if chainKeep[i] {
+			keep[from+i] = true
+		}
+	}
+}
+
+func collectKept(points []Vec, keep []bool) []Vec {
+	n := 0
+	for _, k := range keep {
+		if k {
+			n++
+		}
+	}
+	result := make([]Vec, 0, n)
+	for i, v := range points {
+		if keep[i] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+type vwNode struct {
+	index     int
+	area      float64
+	heapIndex int
+	prev      int
+	next      int
+}
+
+type vwHeap []*vwNode
+
+func (h vwHeap) Len() int { return len(h) }
+func (h vwHeap) Less(i, j int) bool {
+	if h[i].area != h[j].area {
+		return h[i].area < h[j].area
+	}
+	return h[i].index < h[j].index
+}
+func (h vwHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+func (h *vwHeap) Push(x any) {
+	n := x.(*vwNode)
+	n.heapIndex = len(*h)
+	*h = append(*h, n)
+}
+func (h *vwHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	n.heapIndex = -1
+	return n
+}
+
+func simplifyVW(points []Vec, closed bool, minArea float64, count int) []Vec {
+	minPoints := 2
+	if closed {
+		minPoints = 3
+	}
+	if count < minPoints {
+		count = minPoints
+	}
+	if len(points) <= minPoints {
+		return append([]Vec(nil), points...)
+	}
+
+	nodes := make([]vwNode, len(points))
+	for i := range nodes {
+		nodes[i] = vwNode{
+			index: i,
+			prev:  i - 1,
+			next:  i + 1,
+		}
+	}
+	if closed {
+		nodes[0].prev = len(nodes) - 1
+		nodes[len(nodes)-1].next = 0
+	}
+
+	triangleArea := func(n *vwNode) float64 {
+		if n.prev == -1 || n.next == len(nodes) {
+			return math.Inf(1) // Polyline endpoints are never removed
+		}
+		a := points[n.prev]
+		b := points[n.index]
+		c := points[n.next]
+		return math.Abs(b.Sub(a).Cross(c.Sub(a))) * 0.5
+	}
+
+	h := make(vwHeap, 0, len(nodes))
+	for i := range nodes {
+		nodes[i].area = triangleArea(&nodes[i])
+		heap.Push(&h, &nodes[i])
+	}
+
+	numPoints := len(points)
+	maxArea := 0.0
+	// blocked are the nodes that can't be removed right now without
+	// breaking the polygon simplicity. Any removal can unblock them
+	// (not only the removal of their neighbors), so they're re-checked
+	// after every successful removal.
+	var blocked []*vwNode
+	for numPoints > count && h.Len() != 0 {
+		n := h[0]
+		if n.area >= minArea || math.IsInf(n.area, 1) {
+			break
+		}
+		if closed && !canRemoveVertex(points, nodes, n) {
+			heap.Pop(&h)
+			blocked = append(blocked, n)
+			continue
+		}
+
+		heap.Pop(&h)
+		numPoints--
+		// Every point removed later should have at least the same area.
+		// This makes the threshold-based simplification consistent.
+		maxArea = math.Max(maxArea, n.area)
+
+		prev := &nodes[n.prev]
+		next := &nodes[n.next]
+		prev.next = n.next
+		next.prev = n.prev
+		for _, neighbor := range [2]*vwNode{prev, next} {
+			if neighbor.heapIndex == -1 {
+				continue
+			}
+			neighbor.area = math.Max(triangleArea(neighbor), maxArea)
+			heap.Fix(&h, neighbor.heapIndex)
+		}
+		for _, b := range blocked {
+			b.area = math.Max(triangleArea(b), maxArea)
+			heap.Push(&h, b)
+		}
+		blocked = blocked[:0]
+	}
+
+	// Traverse the linked list to keep the original order.
+	result := make([]Vec, 0, numPoints)
+	start := 0
+	if closed {
+		// The first node could be removed for a closed polygon.
+		start = len(nodes)
+		for _, list := range [2][]*vwNode{h, blocked} {
+			for _, n := range list {
+				if n.index < start {
+					start = n.index
+				}
+			}
+		}
+	}
+	i := start
+	for {
+		result = append(result, points[i])
+		i = nodes[i].next
+		if i == start || i == len(nodes) {
+			break
+		}
+	}
+	return result
+}
+
+// canRemoveVertex reports whether removing n from a closed polygon
+// keeps it simple (assuming that it's already simple).
+func canRemoveVertex(points []Vec, nodes []vwNode, n *vwNode) bool {
+	a := points[n.prev]
+	b := points[n.index]
+	c := points[n.next]
+
+	for i := nodes[n.next].next; i != n.prev; i = nodes[i].next {
+		p := points[i]
+		// Any other vertex inside the removed (or added) triangle
+		// means that the shape would change its topology.
+		if p != a && p != c && triangleContains(a, b, c, p) {
+			return false
+		}
+		j := nodes[i].next
+		if j == n.prev {
+			continue // The p-prev edge is adjacent to the new edge
+		}
+		if segmentsIntersect(a, c, p, points[j]) {
+			return false
+		}
+	}
+
+	// The edge that ends at prev is adjacent to the new edge,
+	// but they can still overlap if they're collinear.
+	before := points[nodes[n.prev].prev]
+	if orientation(before, a, c) == 0 && before.Sub(a).Dot(c.Sub(a)) > 0 {
+		return false
+	}
+	after := points[nodes[n.next].next]
+	return !(orientation(a, c, after) == 0 && a.Sub(c).Dot(after.Sub(c)) > 0)
+}
+
+// triangleContains reports whether p is inside the a-b-c triangle or on its border.
+// The triangle can have any winding.
+func triangleContains(a, b, c, p Vec) bool {
+	o1 := orientation(a, b, p)
+	o2 := orientation(b, c, p)
+	o3 := orientation(c, a, p)
+	hasNeg := o1 < 0 || o2 < 0 || o3 < 0
+	hasPos := o1 > 0 || o2 > 0 || o3 > 0
+	return !(hasNeg && hasPos)
+}
+
+// closestPointOnSegment returns a point of the a-b segment that is closest to p.
+func closestPointOnSegment(a, b, p Vec) Vec {
+	ab := b.Sub(a)
+	lenSqr := ab.LenSquared()
+	if lenSqr == 0 {
+		return a
+	}
+	t := Clamp(p.Sub(a).Dot(ab)/lenSqr, 0, 1)
+	return a.Add(ab.Mulf(t))
+}
@@ -0,0 +1,153 @@
+package gmath
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSimplifyRDP(t *testing.T) {
+	tests := []struct {
+		points    []Vec
+		tolerance float64
+		want      []Vec
+	}{
+		{nil, 1, nil},
+		{[]Vec{{0, 0}}, 1, []Vec{{0, 0}}},
+		{[]Vec{{0, 0}, {5, 5}}, 1, []Vec{{0, 0}, {5, 5}}},
+		{[]Vec{{0, 0}, {1, 0}, {2, 0}, {3, 0}}, 0, []Vec{{0, 0}, {3, 0}}},
+		{[]Vec{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 0}}, 0.5, []Vec{{0, 0}, {3, 0}}},
+		{[]Vec{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 0}}, 0.05, []Vec{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 0}}},
+		{[]Vec{{0, 0}, {5, 0}, {5, 0.2}, {5, 5}, {4.9, 7}, {5, 10}}, 0.5, []Vec{{0, 0}, {5, 0}, {5, 10}}},
+	}
+
+	for _, test := range tests {
+		have := SimplifyRDP(test.points, test.tolerance)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Fatalf("SimplifyRDP(%v, %v):\nhave: %v\nwant: %v", test.points, test.tolerance, have, test.want)
+		}
+	}
+}
+
+func TestSimplifyVW(t *testing.T) {
+	points := []Vec{{0, 0}, {1, 0.1}, {2, 0}, {3, 3}, {4, 0}, {5, 0.05}, {6, 0}}
+
+	tests := []struct {
+		minArea float64
+		want    []Vec
+	}{
+		{0, points},
+		{0.2, []Vec{{0, 0}, {2, 0}, {3, 3}, {4, 0}, {6, 0}}},
+		{100, []Vec{{0, 0}, {6, 0}}},
+	}
+	for _, test := range tests {
+		have := SimplifyVW(points, test.minArea)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Fatalf("SimplifyVW(%v, %v):\nhave: %v\nwant: %v", points, test.minArea, have, test.want)
+		}
+	}
+
+	countTests := []struct {
+		count int
+		want  []Vec
+	}{
+		{100, points},
+		{5, []Vec{{0, 0}, {2, 0}, {3, 3}, {4, 0}, {6, 0}}},
+		{3, []Vec{{0, 0}, {3, 3}, {6, 0}}},
+		{0, []Vec{{0, 0}, {6, 0}}},
+	}
+	for _, test := range countTests {
+		have := SimplifyVWCount(points, test.count)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Fatalf("SimplifyVWCount(%v, %v):\nhave: %v\nwant: %v", points, test.count, have, test.want)
+		}
+	}
+}
+
+func TestPolygonSimplify(t *testing.T) {
+	// A noisy circle with a narrow inward notch:
+	// naive simplification tends to cut through it.
+	var noisy Polygon
+	var rng Rand
+	rng.SetSeed(5)
+	for i := 0; i < 64; i++ {
+		angle := Rad(2 * math.Pi * float64(i) / 64)
+		r := 100 + rng.FloatRange(-2, 2)
+		if i == 16 {
+			r = 5
+		}
+		noisy = append(noisy, Vec{}.MoveInDirection(r, angle))
+	}
+	// A thin wall next to another one.
+	comb := Polygon{{0, 0}, {10, 0}, {10, 10}, {5.5, 10}, {5.2, 1}, {4.8, 1}, {4.5, 10}, {0, 10}}
+
+	polygons := []Polygon{
+		noisy,
+		noisy.Reversed(),
+		comb,
+		comb.Reversed(),
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+	}
+
+	for _, p := range polygons {
+		if !p.IsSimple() {
+			t.Fatalf("test polygon %v is not simple", p)
+		}
+		check := func(name string, result Polygon) {
+			t.Helper()
+			if len(result) < 3 {
+				t.Fatalf("%s: too few points: %v", name, result)
+			}
+			if !result.IsSimple() {
+				t.Fatalf("%s: result is not simple: %v", name, result)
+			}
+			if result.IsClockwise() != p.IsClockwise() {
+				t.Fatalf("%s: winding changed: %v", name, result)
+			}
+		}
+		for _, tolerance := range []float64{0, 1, 5, 20, 1000} {
+			check("SimplifyRDP", p.SimplifyRDP(tolerance))
+		}
+		for _, minArea := range []float64{0, 1, 50, 1000, math.Inf(1)} {
+			check("SimplifyVW", p.SimplifyVW(minArea))
+		}
+		for _, count := range []int{0, 3, 4, 10, 100} {
+			result := p.SimplifyVWCount(count)
+			check("SimplifyVWCount", result)
+			if count >= len(p) && len(result) != len(p) {
+				t.Fatalf("SimplifyVWCount(%d) removed points from %v", count, p)
+			}
+		}
+	}
+
+	noisyRDP := noisy.SimplifyRDP(5)
+	if len(noisyRDP) >= len(noisy)/2 {
+		t.Fatalf("SimplifyRDP removed too few points: %d => %d", len(noisy), len(noisyRDP))
+	}
+	if !noisyRDP.Contains(Vec{X: 50}) {
+		t.Fatalf("SimplifyRDP changed the shape too much")
+	}
+}
+
+func TestSimplifyVWBlockedVertex(t *testing.T) {
+	// The {5, -1} vertex has the smallest area, but it can't be removed
+	// while the spike tip {5, -0.5} is inside of its triangle.
+	// The spike tip is not its neighbor, but removing it unblocks the vertex.
+	p := Polygon{{0, 0}, {5, -1}, {10, 0}, {10, 10}, {7, 10}, {5, -0.5}, {3, 10}, {0, 10}}
+	blocked := Vec{5, -1}
+
+	results := []Polygon{
+		p.SimplifyVW(22),
+		p.SimplifyVWCount(4),
+	}
+	for _, result := range results {
+		if !result.IsSimple() {
+			t.Fatalf("result is not simple: %v", result)
+		}
+		for _, v := range result {
+			if v == blocked {
+				t.Fatalf("blocked vertex is not removed: %v", result)
+			}
+		}
+	}
+}
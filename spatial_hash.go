@@ -0,0 +1,433 @@
+package gmath
+
+import (
+	"math"
+)
+
+// SpatialHash is a uniform grid broad-phase structure.
+// The items are stored in all cells that are covered by their bounds.
+//
+// It works best when the items are roughly of the cell size
+// and the world is densely populated.
+// The items that cover too many cells (or have infinite bounds) are
+// not stored in the cells, every query checks them separately.
+//
+// The queries append the results to the provided slice,
+// so they don't allocate if the slice has enough capacity.
+// The queries mark the visited items to avoid the duplicates, so even
+// the read-only queries modify the hash: it's not safe for the concurrent use.
+// A zero value is not usable, use [NewSpatialHash] to create a hash.
+type SpatialHash[T comparable] struct {
+	cellSize    float64
+	invCellSize float64
+
+	cells   map[Ivec[int]][]int32
+	index   map[T]int32
+	entries []spatialHashEntry[T]
+	free    []int32
+
+	// large contains the items that are not stored in the cells.
+	large []int32
+
+	// candidates is a query buffer that is reused between the calls.
+	candidates []int32
+
+	// usedMin and usedMax describe the area of the cells that ever had any items.
+	// The queries never look outside of it, so a query for a huge
+	// area doesn't iterate over the millions of empty cells.
+	usedMin Ivec[int]
+	usedMax Ivec[int]
+
+	// stamp is used to avoid the duplicated query results
+	// without an extra set allocation.
+	stamp uint32
+}
+
+type spatialHashEntry[T comparable] struct {
+	item T
+
+	bounds   Rect
+	circle   Circle
+	isCircle bool
+
+	cellMin Ivec[int]
+	cellMax Ivec[int]
+	large   bool
+
+	stamp uint32
+}
+
+const (
+	// spatialHashMaxCell limits the cell coordinates, so the
+	// huge and infinite values don't overflow the int.
+	spatialHashMaxCell = 1 << 30
+
+	// spatialHashMaxItemCells is a max number of cells an item can be stored in;
+	// the bigger items are kept in a separate list.
+	spatialHashMaxItemCells = 1024
+)
+
+// NewSpatialHash creates an empty spatial hash with the specified cell size.
+func NewSpatialHash[T comparable](cellSize float64) *SpatialHash[T] {
+	if cellSize <= 0 {
+		panic("cellSize must be positive")
+	}
+	h := &SpatialHash[T]{
+		cellSize:    cellSize,
+		invCellSize: 1 / cellSize,
+		cells:       make(map[Ivec[int]][]int32),
+		index:       make(map[T]int32),
+	}
+	h.resetUsedCells()
+	return h
+}
+
+// CellSize returns the grid cell size.
+func (h *SpatialHash[T]) CellSize() float64 { return h.cellSize }
+
+// Len returns the number of items inside the hash.
+func (h *SpatialHash[T]) Len() int { return len(h.index) }
+
+// CellOf returns the grid cell coordinates for the given point.
+//
+// The cell coordinates are clamped to the [-2^30, 2^30] range,
+// so all points that are too far away (or infinite) share the border cells.
+func (h *SpatialHash[T]) CellOf(p Vec) Ivec[int] {
+	return Ivec[int]{
+		X: spatialHashCoord(p.X * h.invCellSize),
+		Y: spatialHashCoord(p.Y * h.invCellSize),
+	}
+}
+
+func spatialHashCoord(v float64) int {
+	v = math.Floor(v)
+	switch {
+	case v >= spatialHashMaxCell:
+		return spatialHashMaxCell
+	case v <= -spatialHashMaxCell:
+		return -spatialHashMaxCell
+	case v == v:
+		return int(v)
+	default:
+		// NaN.
+		return 0
+	}
+}
+
+// Insert adds the item with the given bounds.
+// If the item is already inside the hash, it's updated instead.
+func (h *SpatialHash[T]) Insert(item T, bounds Rect) {
+	h.insert(item, bounds, Circle{}, false)
+}
+
+// InsertCircle is like [SpatialHash.Insert], but the item bounds are a circle.
+func (h *SpatialHash[T]) InsertCircle(item T, c Circle) {
+	h.insert(item, c.Bounds(), c, true)
+}
+
+// Update changes the item bounds.
+// It's an alias for [SpatialHash.Insert] that makes the intention clearer.
+func (h *SpatialHash[T]) Update(item T, bounds Rect) {
+	h.insert(item, bounds, Circle{}, false)
+}
+
+// UpdateCircle is like [SpatialHash.Update], but the item bounds are a circle.
+func (h *SpatialHash[T]) UpdateCircle(item T, c Circle) {
+	h.insert(item, c.Bounds(), c, true)
+}
+
+// Remove deletes the item from the hash.
+// It returns false if there was no such item.
+func (h *SpatialHash[T]) Remove(item T) bool {
+	id, ok := h.index[item]
+	if !ok {
+		return false
+	}
+	h.unlink(id)
+	delete(h.index, item)
+	h.entries[id] = spatialHashEntry[T]{}
+	h.free = append(h.free, id)
+	return true
+}
+
+// Clear removes all items from the hash.
+// The allocated memory is kept for the reuse.
+func (h *SpatialHash[T]) Clear() {
+	for k, ids := range h.cells {
+		h.cells[k] = ids[:0]
+	}
+	for k := range h.index {
+		delete(h.index, k)
+	}
+	h.entries = h.entries[:0]
+	h.free = h.free[:0]
+	h.large = h.large[:0]
+	h.resetUsedCells()
+}
+
+// QueryRect appends all items that overlap the area to dst.
+// The touching items are included.
+// Every item is reported at most once.
+func (h *SpatialHash[T]) QueryRect(dst []T, area Rect) []T {
+	h.collectCandidates(area)
+	for _, id := range h.candidates {
+		if e := &h.entries[id]; e.overlapsRect(area) {
+			dst = append(dst, e.item)
+		}
+	}
+	return dst
+}
+
+// QueryCircle is like [SpatialHash.QueryRect], but the area is a circle.
+func (h *SpatialHash[T]) QueryCircle(dst []T, area Circle) []T {
+	h.collectCandidates(area.Bounds())
+	for _, id := range h.candidates {
+		if e := &h.entries[id]; e.overlapsCircle(area) {
+			dst = append(dst, e.item)
+		}
+	}
+	return dst
+}
+
+// QueryArc appends all items that are inside the arc sector to dst.
+// The arc parameters have the same meaning as in [ArcSectionContains].
+//
+// Unlike other queries, only the item center point is checked:
+// the rect center for [SpatialHash.Insert] and
+// the circle center for [SpatialHash.InsertCircle].
+func (h *SpatialHash[T]) QueryArc(dst []T, angle, measure Rad, r float64, pos Vec) []T {
+	h.collectCandidates(Circle{Center: pos, Radius: r}.Bounds())
+	for _, id := range h.candidates {
+		if e := &h.entries[id]; ArcSectionContains(angle, measure, r, pos, e.center()) {
+			dst = append(dst, e.item)
+		}
+	}
+	return dst
+}
+
+// Pairs appends all pairs of the overlapping items to dst.
+// The touching items are included.
+//
+// Every pair is reported only once, in no particular order.
+func (h *SpatialHash[T]) Pairs(dst [][2]T) [][2]T {
+	for cell, ids := range h.cells {
+		for i, id1 := range ids {
+			e1 := &h.entries[id1]
+			for _, id2 := range ids[i+1:] {
+				e2 := &h.entries[id2]
+				// The items can share several cells;
+				// the pair is only reported for the first one of them.
+				first := Ivec[int]{
+					X: ClampMin(e1.cellMin.X, e2.cellMin.X),
+					Y: ClampMin(e1.cellMin.Y, e2.cellMin.Y),
+				}
+				if first != cell {
+					continue
+				}
+				if e1.overlapsEntry(e2) {
+					dst = append(dst, [2]T{e1.item, e2.item})
+				}
+			}
+		}
+	}
+	// The large items are paired with all other items.
+	for i, id1 := range h.large {
+		e1 := &h.entries[id1]
+		for _, id2 := range h.index {
+			e2 := &h.entries[id2]
+			if id2 == id1 || (e2.large && h.largeIndex(id2) < i) {
+				continue
+			}
+			if e1.overlapsEntry(e2) {
+				dst = append(dst, [2]T{e1.item, e2.item})
+			}
+		}
+	}
+	return dst
+}
+
+// collectCandidates fills the candidates slice with the items
+// that can overlap the area. Every item is added at most once.
+func (h *SpatialHash[T]) collectCandidates(area Rect) {
+	h.nextStamp()
+	h.candidates = h.candidates[:0]
+
+	cellMin, cellMax := h.cellRange(area)
+	cellMin.X = ClampMin(cellMin.X, h.usedMin.X)
+	cellMin.Y = ClampMin(cellMin.Y, h.usedMin.Y)
+	cellMax.X = ClampMax(cellMax.X, h.usedMax.X)
+	cellMax.Y = ClampMax(cellMax.Y, h.usedMax.Y)
+	if cellMin.X > cellMax.X || cellMin.Y > cellMax.Y {
+		h.candidates = append(h.candidates, h.large...)
+		return
+	}
+	if spatialHashNumCells(cellMin, cellMax) > float64(len(h.index)) {
+		// It's cheaper to check every item than to visit all these cells.
+		for _, id := range h.index {
+			h.candidates = append(h.candidates, id)
+		}
+		return
+	}
+
+	for y := cellMin.Y; y <= cellMax.Y; y++ {
+		for x := cellMin.X; x <= cellMax.X; x++ {
+			for _, id := range h.cells[Ivec[int]{X: x, Y: y}] {
+				e := &h.entries[id]
+				if e.stamp == h.stamp {
+					continue
+				}
+				e.stamp = h.stamp
+				h.candidates = append(h.candidates, id)
+			}
+		}
+	}
+	h.candidates = append(h.candidates, h.large...)
+}
+
+func (h *SpatialHash[T]) insert(item T, bounds Rect, c Circle, isCircle bool) {
+	cellMin, cellMax := h.cellRange(bounds)
+	large := spatialHashNumCells(cellMin, cellMax) > spatialHashMaxItemCells
+
+	if id, ok := h.index[item]; ok {
+		e := &h.entries[id]
+		if e.cellMin != cellMin || e.cellMax != cellMax || e.large != large {
+			h.unlink(id)
+			e.cellMin = cellMin
+			e.cellMax = cellMax
+			e.large = large
+			h.link(id)
+		}
+		e.bounds = bounds
+		e.circle = c
+		e.isCircle = isCircle
+		return
+	}
+
+	var id int32
+	if len(h.free) != 0 {
+		id = h.free[len(h.free)-1]
+		h.free = h.free[:len(h.free)-1]
+	} else {
+		id = int32(len(h.entries))
+		h.entries = append(h.entries, spatialHashEntry[T]{})
+	}
+	h.entries[id] = spatialHashEntry[T]{
+		item:     item,
+		bounds:   bounds,
+		circle:   c,
+		isCircle: isCircle,
+		cellMin:  cellMin,
+		cellMax:  cellMax,
+		large:    large,
+	}
+	h.index[item] = id
+	h.link(id)
+}
+
+func (h *SpatialHash[T]) link(id int32) {
+	e := &h.entries[id]
+	if e.large {
+		h.large = append(h.large, id)
+		return
+	}
+	h.usedMin.X = ClampMax(h.usedMin.X, e.cellMin.X)
+	h.usedMin.Y = ClampMax(h.usedMin.Y, e.cellMin.Y)
+	h.usedMax.X = ClampMin(h.usedMax.X, e.cellMax.X)
+	h.usedMax.Y = ClampMin(h.usedMax.Y, e.cellMax.Y)
+	for y := e.cellMin.Y; y <= e.cellMax.Y; y++ {
+		for x := e.cellMin.X; x <= e.cellMax.X; x++ {
+			k := Ivec[int]{X: x, Y: y}
+			h.cells[k] = append(h.cells[k], id)
+		}
+	}
+}
+
+func (h *SpatialHash[T]) unlink(id int32) {
+	e := &h.entries[id]
+	if e.large {
+		i := h.largeIndex(id)
+		h.large[i] = h.large[len(h.large)-1]
+		h.large = h.large[:len(h.large)-1]
+		return
+	}
+	for y := e.cellMin.Y; y <= e.cellMax.Y; y++ {
+		for x := e.cellMin.X; x <= e.cellMax.X; x++ {
+			k := Ivec[int]{X: x, Y: y}
+			ids := h.cells[k]
+			for i, other := range ids {
+				if other == id {
+					ids[i] = ids[len(ids)-1]
+					ids = ids[:len(ids)-1]
+					break
+				}
+			}
+			// Empty cells are kept to avoid the re-allocations
+			// for the objects that move back and forth.
+			h.cells[k] = ids
+		}
+	}
+}
+
+func (h *SpatialHash[T]) largeIndex(id int32) int {
+	for i, other := range h.large {
+		if other == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (h *SpatialHash[T]) cellRange(bounds Rect) (cellMin, cellMax Ivec[int]) {
+	return h.CellOf(bounds.Min), h.CellOf(bounds.Max)
+}
+
+func (h *SpatialHash[T]) resetUsedCells() {
+	h.usedMin = Ivec[int]{X: spatialHashMaxCell, Y: spatialHashMaxCell}
+	h.usedMax = Ivec[int]{X: -spatialHashMaxCell, Y: -spatialHashMaxCell}
+}
+
+// spatialHashNumCells returns the number of cells in the range.
+// The result is a float, so it can't overflow.
+func spatialHashNumCells(cellMin, cellMax Ivec[int]) float64 {
+	return (float64(cellMax.X) - float64(cellMin.X) + 1) * (float64(cellMax.Y) - float64(cellMin.Y) + 1)
+}
+
+func (h *SpatialHash[T]) nextStamp() {
+	h.stamp++
+	if h.stamp == 0 {
+		// The counter overflow: reset the stamps to avoid false positives.
+		for i := range h.entries {
+			h.entries[i].stamp = 0
+		}
+		h.stamp = 1
+	}
+}
+
+func (e *spatialHashEntry[T]) center() Vec {
+	if e.isCircle {
+		return e.circle.Center
+	}
+	return e.bounds.Center()
+}
+
+func (e *spatialHashEntry[T]) overlapsRect(r Rect) bool {
+	if e.isCircle {
+		return r.ClosestPoint(e.circle.Center).DistanceSquaredTo(e.circle.Center) <= e.circle.Radius*e.circle.Radius
+	}
+	return rectsTouch(e.bounds, r)
+}
+
+func (e *spatialHashEntry[T]) overlapsCircle(c Circle) bool {
+	if e.isCircle {
+		r := e.circle.Radius + c.Radius
+		return e.circle.Center.DistanceSquaredTo(c.Center) <= r*r
+	}
+	return e.bounds.ClosestPoint(c.Center).DistanceSquaredTo(c.Center) <= c.Radius*c.Radius
+}
+
+func (e *spatialHashEntry[T]) overlapsEntry(other *spatialHashEntry[T]) bool {
+	if other.isCircle {
+		return e.overlapsCircle(other.circle)
+	}
+	return e.overlapsRect(other.bounds)
+}
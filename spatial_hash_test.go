@@ -0,0 +1,255 @@
+package gmath
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSpatialHashQueries(t *testing.T) {
+	h := NewSpatialHash[int](10)
+	h.Insert(1, Rect{Min: Vec{0, 0}, Max: Vec{5, 5}})
+	h.Insert(2, Rect{Min: Vec{8, 8}, Max: Vec{25, 12}})
+	h.InsertCircle(3, Circle{Center: Vec{40, 40}, Radius: 3})
+	h.InsertCircle(4, Circle{Center: Vec{-20, 5}, Radius: 1})
+
+	tests := []struct {
+		name string
+		run  func(dst []int) []int
+		want []int
+	}{
+		{"rect all", func(dst []int) []int { return h.QueryRect(dst, Rect{Min: Vec{-100, -100}, Max: Vec{100, 100}}) }, []int{1, 2, 3, 4}},
+		{"rect touching", func(dst []int) []int { return h.QueryRect(dst, Rect{Min: Vec{5, 5}, Max: Vec{7, 7}}) }, []int{1}},
+		{"rect wide item", func(dst []int) []int { return h.QueryRect(dst, Rect{Min: Vec{20, 9}, Max: Vec{21, 10}}) }, []int{2}},
+		{"rect circle corner", func(dst []int) []int { return h.QueryRect(dst, Rect{Min: Vec{42.5, 42.5}, Max: Vec{50, 50}}) }, nil},
+		{"circle", func(dst []int) []int { return h.QueryCircle(dst, Circle{Center: Vec{35, 40}, Radius: 2}) }, []int{3}},
+		{"circle miss", func(dst []int) []int { return h.QueryCircle(dst, Circle{Center: Vec{35, 40}, Radius: 1.9}) }, nil},
+		{"circle rect", func(dst []int) []int { return h.QueryCircle(dst, Circle{Center: Vec{6, 6}, Radius: 3}) }, []int{1, 2}},
+		{"arc", func(dst []int) []int { return h.QueryArc(dst, math.Pi/4, math.Pi/2, 60, Vec{0, 0}) }, []int{1, 2, 3}},
+		{"arc narrow", func(dst []int) []int { return h.QueryArc(dst, 0, 1.2, 60, Vec{0, 0}) }, []int{2}},
+		{"arc back", func(dst []int) []int { return h.QueryArc(dst, math.Pi, 1, 30, Vec{0, 0}) }, []int{4}},
+	}
+
+	for _, test := range tests {
+		have := test.run(nil)
+		sort.Ints(have)
+		if len(have) != len(test.want) {
+			t.Fatalf("%s:\nhave: %v\nwant: %v", test.name, have, test.want)
+		}
+		for i := range have {
+			if have[i] != test.want[i] {
+				t.Fatalf("%s:\nhave: %v\nwant: %v", test.name, have, test.want)
+			}
+		}
+	}
+
+	h.Update(2, Rect{Min: Vec{100, 100}, Max: Vec{101, 101}})
+	if have := h.QueryRect(nil, Rect{Min: Vec{8, 8}, Max: Vec{25, 12}}); len(have) != 0 {
+		t.Fatalf("query after update: %v", have)
+	}
+	if !h.Remove(1) || h.Remove(1) {
+		t.Fatal("unexpected Remove result")
+	}
+	if h.Len() != 3 {
+		t.Fatalf("Len(): have %d, want 3", h.Len())
+	}
+	h.Clear()
+	if have := h.QueryRect(nil, Rect{Min: Vec{-1000, -1000}, Max: Vec{1000, 1000}}); len(have) != 0 || h.Len() != 0 {
+		t.Fatalf("query after clear: %v", have)
+	}
+}
+
+func TestSpatialHashQueryArcCenter(t *testing.T) {
+	// QueryArc only checks the item centers:
+	// the items that overlap the arc with their bounds are not reported.
+	h := NewSpatialHash[int](10)
+	h.Insert(1, Rect{Min: Vec{15, -1}, Max: Vec{30, 1}})
+	h.InsertCircle(2, Circle{Center: Vec{10, 0}, Radius: 1})
+	h.Insert(3, Rect{Min: Vec{5, 5}, Max: Vec{30, 30}})
+	h.InsertCircle(4, Circle{Center: Vec{0, -30}, Radius: 25})
+	h.Insert(5, Rect{Min: Vec{18, -1}, Max: Vec{20, 1}})
+
+	have := h.QueryArc(nil, 0, math.Pi/2, 20, Vec{0, 0})
+	sort.Ints(have)
+	want := []int{2, 5}
+	if len(have) != len(want) || have[0] != want[0] || have[1] != want[1] {
+		t.Fatalf("have: %v\nwant: %v", have, want)
+	}
+}
+
+func TestSpatialHashHugeAreas(t *testing.T) {
+	h := NewSpatialHash[int](10)
+	h.Insert(1, Rect{Min: Vec{0, 0}, Max: Vec{5, 5}})
+	h.InsertCircle(2, Circle{Center: Vec{1000, -1000}, Radius: 3})
+	h.Insert(3, Rect{Min: Vec{-1e300, -1e300}, Max: Vec{1e300, 1e300}})
+	h.InsertCircle(4, Circle{Center: Vec{5e6, 5e6}, Radius: 1e6})
+	h.Insert(5, Rect{Min: Vec{math.Inf(-1), 50}, Max: Vec{math.Inf(1), 60}})
+
+	tests := []struct {
+		name string
+		run  func(dst []int) []int
+		want []int
+	}{
+		{"rect huge", func(dst []int) []int { return h.QueryRect(dst, Rect{Min: Vec{-1e300, -1e300}, Max: Vec{1e300, 1e300}}) }, []int{1, 2, 3, 4, 5}},
+		{"rect inf", func(dst []int) []int {
+			return h.QueryRect(dst, Rect{Min: Vec{math.Inf(-1), math.Inf(-1)}, Max: Vec{math.Inf(1), math.Inf(1)}})
+		}, []int{1, 2, 3, 4, 5}},
+		{"rect far", func(dst []int) []int { return h.QueryRect(dst, Rect{Min: Vec{1e299, 1e299}, Max: Vec{1e300, 1e300}}) }, []int{3}},
+		{"rect small", func(dst []int) []int { return h.QueryRect(dst, Rect{Min: Vec{1, 1}, Max: Vec{2, 2}}) }, []int{1, 3}},
+		{"rect line", func(dst []int) []int { return h.QueryRect(dst, Rect{Min: Vec{1e9, 55}, Max: Vec{1e9, 55}}) }, []int{3, 5}},
+		{"circle huge", func(dst []int) []int { return h.QueryCircle(dst, Circle{Radius: 1e300}) }, []int{1, 2, 3, 4, 5}},
+		{"circle inf", func(dst []int) []int { return h.QueryCircle(dst, Circle{Radius: math.Inf(1)}) }, []int{1, 2, 3, 4, 5}},
+		{"circle big", func(dst []int) []int { return h.QueryCircle(dst, Circle{Center: Vec{1000, 0}, Radius: 1e4}) }, []int{1, 2, 3, 5}},
+		{"arc huge", func(dst []int) []int { return h.QueryArc(dst, math.Pi/4, math.Pi/2, 1e300, Vec{}) }, []int{1, 3, 4}},
+		{"arc inf", func(dst []int) []int { return h.QueryArc(dst, -math.Pi/4, math.Pi/2, math.Inf(1), Vec{}) }, []int{2, 3}},
+	}
+
+	for _, test := range tests {
+		have := test.run(nil)
+		sort.Ints(have)
+		if len(have) != len(test.want) {
+			t.Fatalf("%s:\nhave: %v\nwant: %v", test.name, have, test.want)
+		}
+		for i := range have {
+			if have[i] != test.want[i] {
+				t.Fatalf("%s:\nhave: %v\nwant: %v", test.name, have, test.want)
+			}
+		}
+	}
+
+	pairs := h.Pairs(nil)
+	if len(pairs) != 4 {
+		t.Fatalf("Pairs(): have %v, want 4 pairs", pairs)
+	}
+
+	h.Update(3, Rect{Min: Vec{0, 0}, Max: Vec{1, 1}})
+	if have := h.QueryRect(nil, Rect{Min: Vec{1e299, 1e299}, Max: Vec{1e300, 1e300}}); len(have) != 0 {
+		t.Fatalf("query after update: %v", have)
+	}
+	if !h.Remove(4) || !h.Remove(5) {
+		t.Fatal("unexpected Remove result")
+	}
+	if have := h.QueryCircle(nil, Circle{Radius: math.Inf(1)}); len(have) != 3 {
+		t.Fatalf("query after remove: %v", have)
+	}
+}
+
+func TestSpatialHashRandomized(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	h := NewSpatialHash[int](16)
+	type item struct {
+		bounds   Rect
+		circle   Circle
+		isCircle bool
+		alive    bool
+	}
+	items := make([]item, 200)
+
+	randomize := func(i int) {
+		pos := Vec{rng.Float64()*200 - 100, rng.Float64()*200 - 100}
+		if rng.Intn(2) == 0 {
+			c := Circle{Center: pos, Radius: rng.Float64() * 20}
+			items[i] = item{circle: c, bounds: c.Bounds(), isCircle: true, alive: true}
+			h.InsertCircle(i, c)
+		} else {
+			// Some items are too big to be stored in the cells.
+			size := 40.0
+			if rng.Intn(10) == 0 {
+				size = 1000
+			}
+			r := Rect{Min: pos, Max: pos.Add(Vec{rng.Float64() * size, rng.Float64() * size})}
+			items[i] = item{bounds: r, alive: true}
+			h.Insert(i, r)
+		}
+	}
+	for i := range items {
+		randomize(i)
+	}
+
+	overlaps := func(a, b item) bool {
+		switch {
+		case a.isCircle && b.isCircle:
+			r := a.circle.Radius + b.circle.Radius
+			return a.circle.Center.DistanceSquaredTo(b.circle.Center) <= r*r
+		case a.isCircle:
+			return b.bounds.ClosestPoint(a.circle.Center).DistanceSquaredTo(a.circle.Center) <= a.circle.Radius*a.circle.Radius
+		case b.isCircle:
+			return a.bounds.ClosestPoint(b.circle.Center).DistanceSquaredTo(b.circle.Center) <= b.circle.Radius*b.circle.Radius
+		default:
+			return a.bounds.Min.X <= b.bounds.Max.X && b.bounds.Min.X <= a.bounds.Max.X &&
+				a.bounds.Min.Y <= b.bounds.Max.Y && b.bounds.Min.Y <= a.bounds.Max.Y
+		}
+	}
+
+	var buf []int
+	var pairs [][2]int
+	for round := 0; round < 20; round++ {
+		for j := 0; j < 30; j++ {
+			i := rng.Intn(len(items))
+			if rng.Intn(4) == 0 {
+				h.Remove(i)
+				items[i].alive = false
+			} else {
+				randomize(i)
+			}
+		}
+
+		area := Rect{Min: Vec{rng.Float64()*200 - 100, rng.Float64()*200 - 100}}
+		area.Max = area.Min.Add(Vec{rng.Float64() * 60, rng.Float64() * 60})
+		buf = h.QueryRect(buf[:0], area)
+		want := 0
+		for _, it := range items {
+			if it.alive && overlaps(it, item{bounds: area}) {
+				want++
+			}
+		}
+		if len(buf) != want {
+			t.Fatalf("round %d: QueryRect found %d items, want %d", round, len(buf), want)
+		}
+
+		pairs = h.Pairs(pairs[:0])
+		seen := make(map[[2]int]bool)
+		for _, p := range pairs {
+			if p[0] > p[1] {
+				p[0], p[1] = p[1], p[0]
+			}
+			if seen[p] {
+				t.Fatalf("round %d: duplicated pair %v", round, p)
+			}
+			seen[p] = true
+		}
+		wantPairs := 0
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				if items[i].alive && items[j].alive && overlaps(items[i], items[j]) {
+					wantPairs++
+					if !seen[[2]int{i, j}] {
+						t.Fatalf("round %d: missing pair %d-%d", round, i, j)
+					}
+				}
+			}
+		}
+		if len(pairs) != wantPairs {
+			t.Fatalf("round %d: found %d pairs, want %d", round, len(pairs), wantPairs)
+		}
+	}
+}
+
+func TestSpatialHashNoAllocs(t *testing.T) {
+	h := NewSpatialHash[int](10)
+	for i := 0; i < 100; i++ {
+		h.Insert(i, Rect{Min: Vec{float64(i), float64(i)}, Max: Vec{float64(i) + 5, float64(i) + 5}})
+	}
+	buf := make([]int, 0, 128)
+	area := Rect{Min: Vec{10, 10}, Max: Vec{60, 60}}
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = h.QueryRect(buf[:0], area)
+		buf = h.QueryCircle(buf[:0], Circle{Center: Vec{30, 30}, Radius: 20})
+		buf = h.QueryArc(buf[:0], 0, 1, 40, Vec{30, 30})
+		h.Update(50, Rect{Min: Vec{50, 50}, Max: Vec{55, 55}})
+	})
+	if allocs != 0 {
+		t.Fatalf("queries allocate: %v", allocs)
+	}
+}
@@ -0,0 +1,151 @@
+package gmath
+
+import (
+	"math"
+)
+
+// SweepHit describes the first contact of a moving shape with a static one.
+type SweepHit struct {
+	// Time is a fraction of the movement in [0, 1] when the contact happens.
+	// The moving shape position at that time is start+delta*Time.
+	Time float64
+
+	// Normal is a normalized contact surface normal.
+	// It points from the static shape towards the moving one.
+	Normal Vec
+}
+
+// SweepRects checks whether a rectangle moving by delta hits a static rectangle.
+// It returns the first contact info and true if the shapes collide during the movement.
+//
+// If the rectangles already overlap, the hit Time is 0 and
+// the Normal is the axis of a minimal penetration (see [SATRects]).
+// Like with [Rect.Intersects], the touching shapes do not collide,
+// unless the moving rectangle continues to move into the static one.
+func SweepRects(moving Rect, delta Vec, static Rect) (SweepHit, bool) {
+	if moving.IsEmpty() || static.IsEmpty() {
+		return SweepHit{}, false
+	}
+	if p, ok := SATRects(static, moving); ok {
+		return SweepHit{Normal: p.Normal}, true
+	}
+	return sweepAABB(moving.Min, moving.Max, delta, static)
+}
+
+// SweepCircles is like [SweepRects], but for two circles.
+//
+// If the circles already overlap, the Normal is selected like in [SATCircles].
+func SweepCircles(moving Circle, delta Vec, static Circle) (SweepHit, bool) {
+	if p, ok := SATCircles(static, moving); ok {
+		return SweepHit{Normal: p.Normal}, true
+	}
+	// Moving a point against a circle with a combined radius
+	// is equivalent to moving a circle against a circle.
+	length := delta.Len()
+	if length == 0 {
+		return SweepHit{}, false
+	}
+	r := moving.Radius + static.Radius
+	dist, ok := rayCircle(moving.Center, delta.Divf(length), static.Center, r)
+	if !ok || dist > length {
+		return SweepHit{}, false
+	}
+	pos := moving.Center.Add(delta.Mulf(dist / length))
+	normal := pos.Sub(static.Center).Normalized()
+	if normal.Dot(delta) >= 0 {
+		// A tangent movement: the circles only touch.
+		return SweepHit{}, false
+	}
+	return SweepHit{Time: dist / length, Normal: normal}, true
+}
+
+// SweepCircleRect is like [SweepRects], but the moving shape is a circle.
+//
+// If the shapes already overlap, the Normal is selected like in [SATRectCircle].
+func SweepCircleRect(moving Circle, delta Vec, static Rect) (SweepHit, bool) {
+	if static.IsEmpty() {
+		return SweepHit{}, false
+	}
+	if p, ok := SATRectCircle(static, moving); ok {
+		return SweepHit{Normal: p.Normal}, true
+	}
+	if delta.IsZero() {
+		return SweepHit{}, false
+	}
+
+	// The circle center is swept against the rectangle expanded by radius.
+	// Its corners are rounded, so the corner hits require an extra check.
+	r := moving.Radius
+	expanded := Rect{
+		Min: static.Min.Sub(Vec{X: r, Y: r}),
+		Max: static.Max.Add(Vec{X: r, Y: r}),
+	}
+	hit, ok := sweepAABB(moving.Center, moving.Center, delta, expanded)
+	if !ok {
+		return SweepHit{}, false
+	}
+	// A negative time means that the center is already inside the corner area.
+	pos := moving.Center.Add(delta.Mulf(math.Max(hit.Time, 0)))
+	if hit.Time >= 0 && ((pos.X >= static.Min.X && pos.X <= static.Max.X) || (pos.Y >= static.Min.Y && pos.Y <= static.Max.Y)) {
+		return hit, true
+	}
+
+	corner := static.ClosestPoint(pos)
+	length := delta.Len()
+	dist, ok := rayCircle(moving.Center, delta.Divf(length), corner, r)
+	if !ok || dist > length {
+		return SweepHit{}, false
+	}
+	pos = moving.Center.Add(delta.Mulf(dist / length))
+	normal := pos.Sub(corner).Normalized()
+	if normal.Dot(delta) >= 0 {
+		return SweepHit{}, false
+	}
+	return SweepHit{Time: dist / length, Normal: normal}, true
+}
+
+// sweepAABB implements a swept AABB test for the non-overlapping boxes.
+// The moving box can be a point (min=max).
+//
+// The returned hit time is negative if the boxes overlap at the start.
+func sweepAABB(min, max, delta Vec, static Rect) (SweepHit, bool) {
+	entryX, exitX, ok := sweepAxis(min.X, max.X, delta.X, static.Min.X, static.Max.X)
+	if !ok {
+		return SweepHit{}, false
+	}
+	entryY, exitY, ok := sweepAxis(min.Y, max.Y, delta.Y, static.Min.Y, static.Max.Y)
+	if !ok {
+		return SweepHit{}, false
+	}
+
+	entry := math.Max(entryX, entryY)
+	exit := math.Min(exitX, exitY)
+	if entry >= exit || entry > 1 || exit <= 0 {
+		return SweepHit{}, false
+	}
+
+	var hit SweepHit
+	hit.Time = entry
+	if entryX >= entryY {
+		hit.Normal.X = -math.Copysign(1, delta.X)
+	} else {
+		hit.Normal.Y = -math.Copysign(1, delta.Y)
+	}
+	return hit, true
+}
+
+// sweepAxis returns the [entry, exit] movement time interval
+// when the min-max projection overlaps with the staticMin-staticMax projection.
+func sweepAxis(min, max, delta, staticMin, staticMax float64) (entry, exit float64, ok bool) {
+	switch {
+	case delta > 0:
+		return (staticMin - max) / delta, (staticMax - min) / delta, true
+	case delta < 0:
+		return (staticMax - min) / delta, (staticMin - max) / delta, true
+	default:
+		if min < staticMax && staticMin < max {
+			return math.Inf(-1), math.Inf(1), true
+		}
+		return 0, 0, false
+	}
+}
@@ -0,0 +1,221 @@
+package gmath
+
+import (
+	"sort"
+)
+
+// SweepAndPrune is a broad-phase structure that keeps the item bounds
+// sorted along the X axis.
+// It works best for the mostly one-dimensional scenes and coherent movements:
+// the sorting between the frames is incremental.
+//
+// The expected usage is to update the item bounds during the frame
+// and then call [SweepAndPrune.Sweep] once to get the pair changes.
+//
+// The items are identified by the ids returned from [SweepAndPrune.Insert].
+// The removed ids are not reused until the next sweep.
+type SweepAndPrune[T any] struct {
+	items     []sapItem[T]
+	free      []int32
+	freeLater []int32
+
+	// endpoints are sorted by their X coordinate.
+	endpoints []sapEndpoint
+
+	active []int32
+
+	pairs     [][2]int
+	prevPairs [][2]int
+	started   [][2]int
+	ended     [][2]int
+}
+
+type sapItem[T any] struct {
+	bounds Rect
+	value  T
+	alive  bool
+}
+
+type sapEndpoint struct {
+	value float64
+	id    int32
+	isMax bool
+}
+
+// NewSweepAndPrune creates an empty sweep-and-prune structure.
+func NewSweepAndPrune[T any]() *SweepAndPrune[T] {
+	return &SweepAndPrune[T]{}
+}
+
+// Insert adds an item with the given bounds.
+// It returns the item id.
+func (s *SweepAndPrune[T]) Insert(bounds Rect, value T) int {
+	var id int32
+	if len(s.free) != 0 {
+		id = s.free[len(s.free)-1]
+		s.free = s.free[:len(s.free)-1]
+	} else {
+		id = int32(len(s.items))
+		s.items = append(s.items, sapItem[T]{})
+	}
+	s.items[id] = sapItem[T]{bounds: bounds, value: value, alive: true}
+	s.endpoints = append(s.endpoints,
+		sapEndpoint{value: bounds.Min.X, id: id},
+		sapEndpoint{value: bounds.Max.X, id: id, isMax: true})
+	return int(id)
+}
+
+// Remove deletes the item.
+// The pairs with this item are reported as ended during the next sweep.
+//
+// Removing an already removed item is a no-op, unless its id
+// was reused by a new item after that.
+func (s *SweepAndPrune[T]) Remove(id int) {
+	if !s.items[id].alive {
+		return
+	}
+	var zero T
+	s.items[id].alive = false
+	s.items[id].value = zero
+	s.freeLater = append(s.freeLater, int32(id))
+}
+
+// Update changes the item bounds.
+// The changes are applied during the next sweep.
+func (s *SweepAndPrune[T]) Update(id int, bounds Rect) {
+	s.items[id].bounds = bounds
+}
+
+// Value returns the item associated value.
+func (s *SweepAndPrune[T]) Value(id int) T {
+	return s.items[id].value
+}
+
+// Bounds returns the item bounds.
+func (s *SweepAndPrune[T]) Bounds(id int) Rect {
+	return s.items[id].bounds
+}
+
+// Sweep sorts the items and finds the overlapping pairs.
+// The touching items are considered to be overlapping.
+//
+// After the sweep, the current pairs can be accessed with [SweepAndPrune.Pairs];
+// [SweepAndPrune.Started] and [SweepAndPrune.Ended] report the changes since the previous sweep.
+func (s *SweepAndPrune[T]) Sweep() {
+	// Drop the removed items endpoints and refresh the values.
+	endpoints := s.endpoints[:0]
+	for _, e := range s.endpoints {
+		item := &s.items[e.id]
+		if !item.alive {
+			continue
+		}
+		if e.isMax {
+			e.value = item.bounds.Max.X
+		} else {
+			e.value = item.bounds.Min.X
+		}
+		endpoints = append(endpoints, e)
+	}
+	s.endpoints = endpoints
+	s.free = append(s.free, s.freeLater...)
+	s.freeLater = s.freeLater[:0]
+
+	// The insertion sort is almost linear for the nearly sorted data,
+	// which is the case for the coherent movements.
+	for i := 1; i < len(endpoints); i++ {
+		e := endpoints[i]
+		j := i - 1
+		for j >= 0 && sapEndpointLess(e, endpoints[j]) {
+			endpoints[j+1] = endpoints[j]
+			j--
+		}
+		endpoints[j+1] = e
+	}
+
+	s.prevPairs, s.pairs = s.pairs, s.prevPairs[:0]
+	s.active = s.active[:0]
+	for _, e := range endpoints {
+		if e.isMax {
+			for i, id := range s.active {
+				if id == e.id {
+					s.active[i] = s.active[len(s.active)-1]
+					s.active = s.active[:len(s.active)-1]
+					break
+				}
+			}
+			continue
+		}
+		bounds := s.items[e.id].bounds
+		for _, id := range s.active {
+			other := s.items[id].bounds
+			if bounds.Min.Y <= other.Max.Y && other.Min.Y <= bounds.Max.Y {
+				s.pairs = append(s.pairs, sapPair(id, e.id))
+			}
+		}
+		s.active = append(s.active, e.id)
+	}
+	sort.Sort(sapPairSlice(s.pairs))
+
+	// Both pair lists are sorted, so they can be merged.
+	s.started = s.started[:0]
+	s.ended = s.ended[:0]
+	i, j := 0, 0
+	for i < len(s.pairs) || j < len(s.prevPairs) {
+		switch {
+		case j == len(s.prevPairs) || (i < len(s.pairs) && sapPairLess(s.pairs[i], s.prevPairs[j])):
+			s.started = append(s.started, s.pairs[i])
+			i++
+		case i == len(s.pairs) || sapPairLess(s.prevPairs[j], s.pairs[i]):
+			s.ended = append(s.ended, s.prevPairs[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+}
+
+// Pairs returns all overlapping pairs found during the last sweep.
+// Every pair is reported only once, the smaller id goes first.
+//
+// The returned slice is only valid until the next sweep.
+func (s *SweepAndPrune[T]) Pairs() [][2]int { return s.pairs }
+
+// Started returns the pairs that started to overlap during the last sweep.
+//
+// The returned slice is only valid until the next sweep.
+func (s *SweepAndPrune[T]) Started() [][2]int { return s.started }
+
+// Ended returns the pairs that stopped overlapping during the last sweep.
+// This includes the pairs with the removed items.
+//
+// The returned slice is only valid until the next sweep.
+func (s *SweepAndPrune[T]) Ended() [][2]int { return s.ended }
+
+func sapEndpointLess(a, b sapEndpoint) bool {
+	if a.value != b.value {
+		return a.value < b.value
+	}
+	// The min endpoints go first: touching items do overlap.
+	return !a.isMax && b.isMax
+}
+
+func sapPair(a, b int32) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{int(a), int(b)}
+}
+
+func sapPairLess(a, b [2]int) bool {
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+	return a[1] < b[1]
+}
+
+type sapPairSlice [][2]int
+
+func (s sapPairSlice) Len() int           { return len(s) }
+func (s sapPairSlice) Less(i, j int) bool { return sapPairLess(s[i], s[j]) }
+func (s sapPairSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,162 @@
+package gmath
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSweepAndPrune(t *testing.T) {
+	s := NewSweepAndPrune[string]()
+	a := s.Insert(Rect{Max: Vec{10, 10}}, "a")
+	b := s.Insert(Rect{Min: Vec{20, 0}, Max: Vec{30, 10}}, "b")
+	c := s.Insert(Rect{Min: Vec{5, 20}, Max: Vec{25, 30}}, "c")
+
+	checkPairs := func(name string, have, want [][2]int) {
+		t.Helper()
+		if len(have) != len(want) {
+			t.Fatalf("%s:\nhave: %v\nwant: %v", name, have, want)
+		}
+		for i := range have {
+			if have[i] != want[i] {
+				t.Fatalf("%s:\nhave: %v\nwant: %v", name, have, want)
+			}
+		}
+	}
+
+	s.Sweep()
+	checkPairs("pairs", s.Pairs(), nil)
+
+	// a touches b.
+	s.Update(a, Rect{Min: Vec{10, 0}, Max: Vec{20, 10}})
+	s.Sweep()
+	checkPairs("pairs", s.Pairs(), [][2]int{{a, b}})
+	checkPairs("started", s.Started(), [][2]int{{a, b}})
+	checkPairs("ended", s.Ended(), nil)
+
+	// The X projections overlap, but Y projections don't.
+	s.Update(b, Rect{Min: Vec{20, 11}, Max: Vec{30, 19}})
+	s.Update(c, Rect{Min: Vec{5, 10}, Max: Vec{25, 30}})
+	s.Sweep()
+	checkPairs("pairs", s.Pairs(), [][2]int{{a, c}, {b, c}})
+	checkPairs("started", s.Started(), [][2]int{{a, c}, {b, c}})
+	checkPairs("ended", s.Ended(), [][2]int{{a, b}})
+
+	s.Sweep()
+	checkPairs("started", s.Started(), nil)
+	checkPairs("ended", s.Ended(), nil)
+
+	s.Remove(c)
+	if d := s.Insert(Rect{Max: Vec{100, 100}}, "d"); d == c {
+		t.Fatal("removed id is reused before the sweep")
+	}
+	s.Sweep()
+	checkPairs("ended", s.Ended(), [][2]int{{a, c}, {b, c}})
+	if s.Value(a) != "a" || s.Value(b) != "b" {
+		t.Fatal("values changed")
+	}
+}
+
+func TestSweepAndPruneDoubleRemove(t *testing.T) {
+	s := NewSweepAndPrune[int]()
+	a := s.Insert(Rect{Max: Vec{10, 10}}, 1)
+	s.Insert(Rect{Min: Vec{5, 5}, Max: Vec{15, 15}}, 2)
+	s.Sweep()
+
+	s.Remove(a)
+	s.Remove(a)
+	s.Sweep()
+	s.Remove(a)
+	s.Sweep()
+
+	x := s.Insert(Rect{Max: Vec{1, 1}}, 3)
+	y := s.Insert(Rect{Max: Vec{1, 1}}, 4)
+	if x == y {
+		t.Fatalf("the same id %d is returned twice", x)
+	}
+	s.Sweep()
+	if len(s.Pairs()) != 1 {
+		t.Fatalf("Pairs: %v", s.Pairs())
+	}
+	if s.Value(x) != 3 || s.Value(y) != 4 {
+		t.Fatal("values changed")
+	}
+}
+
+func TestSweepAndPruneRandomized(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := NewSweepAndPrune[int]()
+
+	items := make(map[int]Rect)
+	for i := 0; i < 100; i++ {
+		pos := Vec{rng.Float64() * 1000, rng.Float64() * 50}
+		r := Rect{Min: pos, Max: pos.Add(Vec{rng.Float64() * 30, rng.Float64() * 30})}
+		items[s.Insert(r, i)] = r
+	}
+
+	prev := make(map[[2]int]bool)
+	for frame := 0; frame < 100; frame++ {
+		for id, r := range items {
+			switch rng.Intn(20) {
+			case 0:
+				s.Remove(id)
+				delete(items, id)
+			default:
+				r = r.Add(Vec{rng.Float64()*10 - 5, rng.Float64()*2 - 1})
+				items[id] = r
+				s.Update(id, r)
+			}
+		}
+		for i := 0; i < 5; i++ {
+			pos := Vec{rng.Float64() * 1000, rng.Float64() * 50}
+			r := Rect{Min: pos, Max: pos.Add(Vec{rng.Float64() * 30, rng.Float64() * 30})}
+			items[s.Insert(r, i)] = r
+		}
+		s.Sweep()
+
+		cur := make(map[[2]int]bool)
+		for id1, r1 := range items {
+			for id2, r2 := range items {
+				if id1 < id2 && rectsTouch(r1, r2) {
+					cur[[2]int{id1, id2}] = true
+				}
+			}
+		}
+		if len(s.Pairs()) != len(cur) {
+			t.Fatalf("frame %d: found %d pairs, want %d", frame, len(s.Pairs()), len(cur))
+		}
+		for _, p := range s.Pairs() {
+			if !cur[p] {
+				t.Fatalf("frame %d: unexpected pair %v", frame, p)
+			}
+		}
+		numStarted := 0
+		for p := range cur {
+			if !prev[p] {
+				numStarted++
+			}
+		}
+		for _, p := range s.Started() {
+			if !cur[p] || prev[p] {
+				t.Fatalf("frame %d: unexpected started pair %v", frame, p)
+			}
+		}
+		if len(s.Started()) != numStarted {
+			t.Fatalf("frame %d: found %d started pairs, want %d", frame, len(s.Started()), numStarted)
+		}
+		numEnded := 0
+		for p := range prev {
+			if !cur[p] {
+				numEnded++
+			}
+		}
+		for _, p := range s.Ended() {
+			if cur[p] || !prev[p] {
+				t.Fatalf("frame %d: unexpected ended pair %v", frame, p)
+			}
+		}
+		if len(s.Ended()) != numEnded {
+			t.Fatalf("frame %d: found %d ended pairs, want %d", frame, len(s.Ended()), numEnded)
+		}
+		prev = cur
+	}
+}
@@ -0,0 +1,108 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSweepRects(t *testing.T) {
+	wall := Rect{Min: Vec{10, 0}, Max: Vec{11, 100}}
+
+	tests := []struct {
+		moving Rect
+		delta  Vec
+		hit    bool
+		time   float64
+		normal Vec
+	}{
+		{Rect{Min: Vec{0, 10}, Max: Vec{2, 12}}, Vec{100, 0}, true, 0.08, Vec{-1, 0}},
+		{Rect{Min: Vec{0, 10}, Max: Vec{2, 12}}, Vec{7, 0}, false, 0, Vec{}},
+		{Rect{Min: Vec{0, 10}, Max: Vec{2, 12}}, Vec{-100, 0}, false, 0, Vec{}},
+		{Rect{Min: Vec{20, 10}, Max: Vec{22, 12}}, Vec{-20, 5}, true, 0.45, Vec{1, 0}},
+		{Rect{Min: Vec{0, -10}, Max: Vec{2, -8}}, Vec{20, 20}, true, 0.4, Vec{-1, 0}},
+		{Rect{Min: Vec{10, -10}, Max: Vec{11, -8}}, Vec{0, 20}, true, 0.4, Vec{0, -1}},
+		{Rect{Min: Vec{8, 10}, Max: Vec{10, 12}}, Vec{0, 50}, false, 0, Vec{}},
+		{Rect{Min: Vec{8, 10}, Max: Vec{10, 12}}, Vec{1, 0}, true, 0, Vec{-1, 0}},
+		{Rect{Min: Vec{8, 10}, Max: Vec{10.5, 12}}, Vec{0, 0}, true, 0, Vec{-1, 0}},
+		{Rect{Min: Vec{0, -3}, Max: Vec{2, -1}}, Vec{100, 0}, false, 0, Vec{}},
+	}
+
+	for _, test := range tests {
+		have, ok := SweepRects(test.moving, test.delta, wall)
+		if ok != test.hit {
+			t.Fatalf("SweepRects(%v, %v):\nhave: %v\nwant: %v", test.moving, test.delta, ok, test.hit)
+		}
+		if ok && (!EqualApprox(have.Time, test.time) || have.Normal != test.normal) {
+			t.Fatalf("SweepRects(%v, %v):\nhave: %v %v\nwant: %v %v", test.moving, test.delta, have.Time, have.Normal, test.time, test.normal)
+		}
+	}
+}
+
+func TestSweepCircles(t *testing.T) {
+	static := Circle{Center: Vec{10, 0}, Radius: 2}
+
+	tests := []struct {
+		moving Circle
+		delta  Vec
+		hit    bool
+		time   float64
+		normal Vec
+	}{
+		{Circle{Center: Vec{0, 0}, Radius: 1}, Vec{20, 0}, true, 0.35, Vec{-1, 0}},
+		{Circle{Center: Vec{0, 0}, Radius: 1}, Vec{5, 0}, false, 0, Vec{}},
+		{Circle{Center: Vec{0, 0}, Radius: 1}, Vec{-20, 0}, false, 0, Vec{}},
+		{Circle{Center: Vec{0, 3}, Radius: 1}, Vec{20, 0}, false, 0, Vec{}},
+		{Circle{Center: Vec{0, 1.5}, Radius: 1}, Vec{20, 0}, true, (10 - math.Sqrt(9-2.25)) / 20, Vec{-math.Sqrt(9-2.25) / 3, 0.5}},
+		{Circle{Center: Vec{10, -10}, Radius: 1}, Vec{0, 10}, true, 0.7, Vec{0, -1}},
+		{Circle{Center: Vec{11, 0}, Radius: 1}, Vec{}, true, 0, Vec{1, 0}},
+	}
+
+	for _, test := range tests {
+		have, ok := SweepCircles(test.moving, test.delta, static)
+		if ok != test.hit {
+			t.Fatalf("SweepCircles(%v, %v):\nhave: %v\nwant: %v", test.moving, test.delta, ok, test.hit)
+		}
+		if ok && (!EqualApprox(have.Time, test.time) || !have.Normal.EqualApprox(test.normal)) {
+			t.Fatalf("SweepCircles(%v, %v):\nhave: %v %v\nwant: %v %v", test.moving, test.delta, have.Time, have.Normal, test.time, test.normal)
+		}
+	}
+}
+
+func TestSweepCircleRect(t *testing.T) {
+	static := Rect{Min: Vec{10, 10}, Max: Vec{20, 20}}
+
+	tests := []struct {
+		moving Circle
+		delta  Vec
+		hit    bool
+		time   float64
+		normal Vec
+	}{
+		{Circle{Center: Vec{0, 15}, Radius: 2}, Vec{20, 0}, true, 0.4, Vec{-1, 0}},
+		{Circle{Center: Vec{15, 30}, Radius: 2}, Vec{0, -20}, true, 0.4, Vec{0, 1}},
+		{Circle{Center: Vec{0, 15}, Radius: 2}, Vec{5, 0}, false, 0, Vec{}},
+		{Circle{Center: Vec{0, 15}, Radius: 2}, Vec{0, 50}, false, 0, Vec{}},
+
+		// Corner hits.
+		{Circle{Center: Vec{0, 9}, Radius: 2}, Vec{20, 0}, true, (10 - math.Sqrt(3)) / 20, Vec{-math.Sqrt(3) / 2, -0.5}},
+		{Circle{Center: Vec{0, 0}, Radius: 1}, Vec{20, 20}, true, (10 - math.Sqrt2/2) / 20, Vec{-math.Sqrt2 / 2, -math.Sqrt2 / 2}},
+		{Circle{Center: Vec{0, 7.9}, Radius: 2}, Vec{20, 0}, false, 0, Vec{}},
+
+		// The circle center is inside the expanded rect corner area, but outside of the rounded corner.
+		{Circle{Center: Vec{8.5, 8.5}, Radius: 2}, Vec{5, 5}, true, (1.5 - math.Sqrt2) / 5, Vec{-math.Sqrt2 / 2, -math.Sqrt2 / 2}},
+		{Circle{Center: Vec{8.5, 8.5}, Radius: 2}, Vec{-5, -5}, false, 0, Vec{}},
+
+		// Already overlapping.
+		{Circle{Center: Vec{9, 15}, Radius: 2}, Vec{}, true, 0, Vec{-1, 0}},
+	}
+
+	for _, test := range tests {
+		have, ok := SweepCircleRect(test.moving, test.delta, static)
+		if ok != test.hit {
+			t.Fatalf("SweepCircleRect(%v, %v):\nhave: %v\nwant: %v", test.moving, test.delta, ok, test.hit)
+		}
+		if ok && (!EqualApprox(have.Time, test.time) || !have.Normal.EqualApprox(test.normal)) {
+			t.Fatalf("SweepCircleRect(%v, %v):\nhave: %v %v\nwant: %v %v", test.moving, test.delta, have.Time, have.Normal, test.time, test.normal)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package gmath
+
+// Transform is a scene graph node: a local position, rotation and scale
+// that are applied relative to the optional parent transform.
+//
+// The world transformation matrix is cached and recalculated lazily
+// only when the node or any of its ancestors were changed.
+//
+// The zero value is an identity transform without a parent.
+//
+// For the simple cases where only the position matters, a [Pos] is usually enough.
+type Transform struct {
+	parent *Transform
+
+	pos      Vec
+	rotation Rad
+	// scale is stored with -1 offset to make the zero value an identity transform.
+	scale1 Vec
+
+	local Affine2D
+	world Affine2D
+	// worldInv is only valid if worldInvValid is set.
+	worldInv Affine2D
+
+	// version is incremented every time the world matrix is recalculated.
+	// The children compare it with their parentVersion to detect the changes.
+	version       uint64
+	parentVersion uint64
+
+	localDirty    bool
+	worldDirty    bool
+	worldInvValid bool
+}
+
+// NewTransform creates a transform with the given local position and no parent.
+func NewTransform(pos Vec) *Transform {
+	t := &Transform{}
+	t.SetLocalPos(pos)
+	return t
+}
+
+// Parent returns the parent transform.
+// A nil parent means that the local space is the world space.
+func (t *Transform) Parent() *Transform { return t.parent }
+
+// SetParent binds the transform to a new parent.
+// The local values are preserved, so the world position will probably change.
+// A nil parent detaches the transform.
+//
+// If the new parent is t itself or any of its descendants,
+// the parent is not changed and false is returned.
+func (t *Transform) SetParent(parent *Transform) bool {
+	for p := parent; p != nil; p = p.parent {
+		if p == t {
+			return false
+		}
+	}
+	t.parent = parent
+	t.worldDirty = true
+	return true
+}
+
+// LocalPos returns the position relative to the parent.
+func (t *Transform) LocalPos() Vec { return t.pos }
+
+// SetLocalPos changes the position relative to the parent.
+func (t *Transform) SetLocalPos(pos Vec) {
+	t.pos = pos
+	t.localDirty = true
+}
+
+// Translate moves the transform inside the parent space.
+func (t *Transform) Translate(offset Vec) {
+	t.SetLocalPos(t.pos.Add(offset))
+}
+
+// Rotation returns the rotation relative to the parent.
+func (t *Transform) Rotation() Rad { return t.rotation }
+
+// SetRotation changes the rotation relative to the parent.
+func (t *Transform) SetRotation(angle Rad) {
+	t.rotation = angle
+	t.localDirty = true
+}
+
+// Rotate adds the angle to the current rotation.
+func (t *Transform) Rotate(angle Rad) {
+	t.SetRotation(t.rotation + angle)
+}
+
+// Scale returns the scale relative to the parent.
+func (t *Transform) Scale() Vec {
+	return Vec{X: t.scale1.X + 1, Y: t.scale1.Y + 1}
+}
+
+// SetScale changes the scale relative to the parent.
+func (t *Transform) SetScale(scale Vec) {
+	t.scale1 = Vec{X: scale.X - 1, Y: scale.Y - 1}
+	t.localDirty = true
+}
+
+// LocalMatrix returns a matrix that maps the local space into the parent space.
+// The scale is applied first, then the rotation and the translation.
+func (t *Transform) LocalMatrix() Affine2D {
+	t.updateLocal()
+	return t.local
+}
+
+// WorldMatrix returns a matrix that maps the local space into the world space.
+func (t *Transform) WorldMatrix() Affine2D {
+	t.updateWorld()
+	return t.world
+}
+
+// WorldPos returns the transform origin in the world space.
+func (t *Transform) WorldPos() Vec {
+	t.updateWorld()
+	return Vec{X: t.world.tx, Y: t.world.ty}
+}
+
+// WorldRotation returns the accumulated rotation in the world space.
+// See [Affine2D.Decompose] for the negative scale notes.
+func (t *Transform) WorldRotation() Rad {
+	t.updateWorld()
+	_, rotation, _ := t.world.Decompose()
+	return rotation
+}
+
+// ToWorld converts a point from the local space into the world space.
+func (t *Transform) ToWorld(local Vec) Vec {
+	t.updateWorld()
+	return t.world.Apply(local)
+}
+
+// ToLocal converts a point from the world space into the local space.
+//
+// If the world matrix is not invertible (e.g. there is a zero scale somewhere
+// in the hierarchy), the local space origin is returned.
+func (t *Transform) ToLocal(world Vec) Vec {
+	t.updateWorld()
+	if !t.worldInvValid {
+		inv, ok := t.world.Inverse()
+		if !ok {
+			return Vec{}
+		}
+		t.worldInv = inv
+		t.worldInvValid = true
+	}
+	return t.worldInv.Apply(world)
+}
+
+func (t *Transform) updateLocal() {
+	if !t.localDirty {
+		return
+	}
+	t.localDirty = false
+	t.local.Reset()
+	t.local.Scale(t.Scale())
+	t.local.Rotate(t.rotation)
+	t.local.Translate(t.pos)
+	t.worldDirty = true
+}
+
+func (t *Transform) updateWorld() {
+	t.updateLocal()
+	if t.parent == nil {
+		if t.worldDirty {
+			t.setWorld(t.local)
+		}
+		return
+	}
+	t.parent.updateWorld()
+	if t.worldDirty || t.parentVersion != t.parent.version {
+		t.parentVersion = t.parent.version
+		t.setWorld(t.local.Then(t.parent.world))
+	}
+}
+
+func (t *Transform) setWorld(m Affine2D) {
+	t.world = m
+	t.worldDirty = false
+	t.worldInvValid = false
+	t.version++
+}
@@ -0,0 +1,101 @@
+package gmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTransformZero(t *testing.T) {
+	var tf Transform
+	if !tf.WorldMatrix().IsIdentity() {
+		t.Fatal("zero transform world matrix is not identity")
+	}
+	if tf.Scale() != (Vec{1, 1}) {
+		t.Fatalf("zero transform scale: %v", tf.Scale())
+	}
+}
+
+func TestTransformHierarchy(t *testing.T) {
+	root := NewTransform(Vec{100, 50})
+	root.SetRotation(math.Pi / 2)
+
+	child := NewTransform(Vec{10, 0})
+	child.SetScale(Vec{2, 2})
+	child.SetParent(root)
+
+	leaf := NewTransform(Vec{1, 0})
+	leaf.SetParent(child)
+
+	tests := []struct {
+		node  *Transform
+		local Vec
+		world Vec
+	}{
+		{root, Vec{}, Vec{100, 50}},
+		{root, Vec{1, 0}, Vec{100, 51}},
+		{child, Vec{}, Vec{100, 60}},
+		{child, Vec{1, 0}, Vec{100, 62}},
+		{leaf, Vec{}, Vec{100, 62}},
+		{leaf, Vec{0, 1}, Vec{98, 62}},
+	}
+	for _, test := range tests {
+		if have := test.node.ToWorld(test.local); !have.EqualApprox(test.world) {
+			t.Fatalf("ToWorld(%v):\nhave: %v\nwant: %v", test.local, have, test.world)
+		}
+		if have := test.node.ToLocal(test.world); !have.EqualApprox(test.local) {
+			t.Fatalf("ToLocal(%v):\nhave: %v\nwant: %v", test.world, have, test.local)
+		}
+	}
+	if have := leaf.WorldRotation(); !have.EqualApprox(math.Pi / 2) {
+		t.Fatalf("leaf WorldRotation: %v", have)
+	}
+
+	// The ancestor changes should invalidate the cached world transforms.
+	root.Translate(Vec{-100, -50})
+	root.SetRotation(0)
+	if have := leaf.WorldPos(); !have.EqualApprox(Vec{12, 0}) {
+		t.Fatalf("leaf WorldPos after root change: %v", have)
+	}
+	child.SetScale(Vec{1, 1})
+	if have := leaf.WorldPos(); !have.EqualApprox(Vec{11, 0}) {
+		t.Fatalf("leaf WorldPos after child change: %v", have)
+	}
+	leaf.SetParent(root)
+	if have := leaf.WorldPos(); !have.EqualApprox(Vec{1, 0}) {
+		t.Fatalf("leaf WorldPos after reparenting: %v", have)
+	}
+	leaf.SetParent(nil)
+	root.Translate(Vec{5, 5})
+	if have := leaf.WorldPos(); !have.EqualApprox(Vec{1, 0}) {
+		t.Fatalf("detached leaf WorldPos: %v", have)
+	}
+}
+
+func TestTransformCycle(t *testing.T) {
+	a := NewTransform(Vec{})
+	b := NewTransform(Vec{})
+	c := NewTransform(Vec{})
+	b.SetParent(a)
+	c.SetParent(b)
+
+	if a.SetParent(a) {
+		t.Fatal("self parent is accepted")
+	}
+	if a.SetParent(c) {
+		t.Fatal("descendant parent is accepted")
+	}
+	if a.Parent() != nil {
+		t.Fatal("parent is changed after a failed SetParent")
+	}
+	if !c.SetParent(a) {
+		t.Fatal("valid parent is rejected")
+	}
+}
+
+func TestTransformSingular(t *testing.T) {
+	tf := NewTransform(Vec{10, 10})
+	tf.SetScale(Vec{0, 1})
+	if have := tf.ToLocal(Vec{20, 20}); have != (Vec{}) {
+		t.Fatalf("ToLocal for a singular transform: %v", have)
+	}
+}
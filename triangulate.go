@@ -0,0 +1,501 @@
+package gmath
+
+import (
+	"sort"
+)
+
+// Triangulate splits a polygon into triangles using the ear clipping method.
+//
+// The outer polygon can have optional holes.
+// Both outer polygon and holes can use any winding order.
+//
+// The result is a list of vertex indices where every three
+// indices form a triangle. Indices refer to a virtual vertex list
+// that is formed by concatenating the outer polygon vertices
+// with the vertices of every hole (in order).
+// This layout is compatible with Ebitengine DrawTriangles function.
+//
+// Collinear and duplicated vertices are tolerated, but
+// they may be not referenced by any of the resulting triangles.
+// Self-intersecting polygons are handled on the best effort basis.
+//
+// The result is deterministic: the same input always produces the same output.
+//
+// This function panics if the total number of vertices exceeds 65536.
+func Triangulate(outer Polygon, holes ...Polygon) []uint16 {
+	numVertices := len(outer)
+	for _, h := range holes {
+		numVertices += len(h)
+	}
+	if numVertices > 0xffff+1 {
+		panic("too many vertices for Triangulate")
+	}
+
+	var t triangulator
+	t.pool = make([]earNode, 0, numVertices+2*len(holes))
+
+	outerNode := t.linkedList(outer, 0, true)
+	if outerNode == nil || outerNode.next == outerNode.prev {
+		return nil
+	}
+
+	t.result = make([]uint16, 0, (numVertices+2*len(holes)-2)*3)
+
+	if len(holes) != 0 {
+		outerNode = t.eliminateHoles(outer, holes, outerNode)
+	}
+
+	t.earcutLinked(outerNode, 0)
+
+	return t.result
+}
+
+// triangulator implements an ear clipping algorithm that
+// is based on the approach used by the earcut library.
+//
+// The polygon is represented as a circular doubly-linked list of vertices.
+// The holes are merged into the outer polygon by creating bridges
+// (pairs of coinciding edges), so the ear clipping operates on
+// a single (weakly) simple polygon.
+type triangulator struct {
+	pool   []earNode
+	result []uint16
+}
+
+type earNode struct {
+	i    int
+	pos  Vec
+	prev *earNode
+	next *earNode
+
+	// steiner nodes are never removed as collinear.
+	steiner bool
+}
+
+func (t *triangulator) newNode(i int, pos Vec) *earNode {
+	if len(t.pool) == cap(t.pool) {
+		// Allocate a new chunk instead of growing the old one,
+		// so the existing node pointers remain valid.
+		t.pool = make([]earNode, 0, 16)
+	}
+	t.pool = append(t.pool, earNode{i: i, pos: pos})
+	return &t.pool[len(t.pool)-1]
+}
+
+func (t *triangulator) insertNode(i int, pos Vec, last *earNode) *earNode {
+	p := t.newNode(i, pos)
+	if last == nil {
+		p.prev = p
+		p.next = p
+	} else {
+		p.next = last.next
+		p.prev = last
+		last.next.prev = p
+		last.next = p
+	}
+	return p
+}
+
+func (t *triangulator) linkedList(points Polygon, offset int, clockwise bool) *earNode {
+	var last *earNode
+	if clockwise == (points.SignedArea() > 0) {
+		for i, pos := range points {
+			last = t.insertNode(offset+i, pos, last)
+		}
+	} else {
+		for i := len(points) - 1; i >= 0; i-- {
+			last = t.insertNode(offset+i, points[i], last)
+		}
+	}
+	if last != nil && last.pos == last.next.pos {
+		removeEarNode(last)
+		last = last.next
+	}
+	return last
+}
+
+func (t *triangulator) emit(a, b, c *earNode) {
+	t.result = append(t.result, uint16(a.i), uint16(b.i), uint16(c.i))
+}
+
+func (t *triangulator) earcutLinked(ear *earNode, pass int) {
+	if ear == nil {
+		return
+	}
+
+	stop := ear
+	for ear.prev != ear.next {
+		prev := ear.prev
+		next := ear.next
+
+		if isEar(ear) {
+			t.emit(prev, ear, next)
+			removeEarNode(ear)
+			// Skipping the next vertex leads to less sliver triangles.
+			ear = next.next
+			stop = next.next
+			continue
+		}
+
+		ear = next
+		if ear == stop {
+			// No ears found during the full loop.
+			// Try to recover by cleaning up the remaining polygon.
+			switch pass {
+			case 0:
+				t.earcutLinked(filterEarNodes(ear, nil), 1)
+			case 1:
+				ear = t.cureLocalIntersections(filterEarNodes(ear, nil))
+				t.earcutLinked(ear, 2)
+			case 2:
+				t.splitEarcut(ear)
+			}
+			break
+		}
+	}
+}
+
+func (t *triangulator) cureLocalIntersections(start *earNode) *earNode {
+	p := start
+	for {
+		a := p.prev
+		b := p.next.next
+		if a.pos != b.pos && earSegmentsIntersect(a, p, p.next, b) && locallyInside(a, b) && locallyInside(b, a) {
+			t.emit(a, p, b)
+			removeEarNode(p)
+			removeEarNode(p.next)
+			p = b
+			start = b
+		}
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	return filterEarNodes(p, nil)
+}
+
+func (t *triangulator) splitEarcut(start *earNode) {
+	// Look for a valid diagonal that divides the polygon into two.
+	a := start
+	for {
+		b := a.next.next
+		for b != a.prev {
+			if a.i != b.i && isValidDiagonal(a, b) {
+				c := t.splitPolygon(a, b)
+				a = filterEarNodes(a, a.next)
+				c = filterEarNodes(c, c.next)
+				t.earcutLinked(a, 0)
+				t.earcutLinked(c, 0)
+				return
+			}
+			b = b.next
+		}
+		a = a.next
+		if a == start {
+			break
+		}
+	}
+}
+
+func (t *triangulator) eliminateHoles(outer Polygon, holes []Polygon, outerNode *earNode) *earNode {
+	queue := make([]*earNode, 0, len(holes))
+	offset := len(outer)
+	for _, h := range holes {
+		list := t.linkedList(h, offset, false)
+		offset += len(h)
+		if list == nil {
+			continue
+		}
+		if list == list.next {
+			list.steiner = true
+		}
+		queue = append(queue, leftmostEarNode(list))
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].pos.X < queue[j].pos.X
+	})
+
+	for _, hole := range queue {
+		outerNode = t.eliminateHole(hole, outerNode)
+	}
+	return outerNode
+}
+
+func (t *triangulator) eliminateHole(hole, outerNode *earNode) *earNode {
+	bridge := findHoleBridge(hole, outerNode)
+	if bridge == nil {
+		return outerNode
+	}
+	bridgeReverse := t.splitPolygon(bridge, hole)
+	filterEarNodes(bridgeReverse, bridgeReverse.next)
+	return filterEarNodes(bridge, bridge.next)
+}
+
+// splitPolygon links a and b with a bridge.
+// If a and b belong to the same polygon, it's split into two;
+// if they belong to the different polygons (outer and a hole), they're merged.
+func (t *triangulator) splitPolygon(a, b *earNode) *earNode {
+	a2 := t.newNode(a.i, a.pos)
+	b2 := t.newNode(b.i, b.pos)
+	an := a.next
+	bp := b.prev
+
+	a.next = b
+	b.prev = a
+
+	a2.next = an
+	an.prev = a2
+
+	b2.next = a2
+	a2.prev = b2
+
+	bp.next = b2
+	b2.prev = bp
+
+	return b2
+}
+
+func removeEarNode(p *earNode) {
+	p.next.prev = p.prev
+	p.prev.next = p.next
+}
+
+// filterEarNodes removes duplicated and collinear vertices.
+func filterEarNodes(start, end *earNode) *earNode {
+	if start == nil {
+		return start
+	}
+	if end == nil {
+		end = start
+	}
+
+	p := start
+	for {
+		again := false
+		if !p.steiner && (p.pos == p.next.pos || earArea(p.prev, p, p.next) == 0) {
+			removeEarNode(p)
+			p = p.prev
+			end = p
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+		if !again && p == end {
+			break
+		}
+	}
+
+	return end
+}
+
+func isEar(ear *earNode) bool {
+	a := ear.prev
+	b := ear
+	c := ear.next
+
+	if earArea(a, b, c) <= 0 {
+		return false // Reflex or collinear, can't be an ear
+	}
+
+	// Make sure that there are no other points inside the potential ear.
+	for p := c.next; p != a; p = p.next {
+		if pointInTriangle(a.pos, b.pos, c.pos, p.pos) && earArea(p.prev, p, p.next) <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func findHoleBridge(hole, outerNode *earNode) *earNode {
+	p := outerNode
+	h := hole.pos
+	qx := 0.0
+	var m *earNode
+
+	// Find a segment intersected by a ray from the hole's leftmost point to the left.
+	// A segment's endpoint with lesser X will be a potential connection point.
+	for {
+		if h.Y <= p.pos.Y && h.Y >= p.next.pos.Y && p.next.pos.Y != p.pos.Y {
+			x := p.pos.X + (h.Y-p.pos.Y)*(p.next.pos.X-p.pos.X)/(p.next.pos.Y-p.pos.Y)
+			if x <= h.X && (m == nil || x > qx) {
+				qx = x
+				m = p
+				if p.next.pos.X < p.pos.X {
+					m = p.next
+				}
+				if x == h.X {
+					// The hole touches the outer segment.
+					return m
+				}
+			}
+		}
+		p = p.next
+		if p == outerNode {
+			break
+		}
+	}
+
+	if m == nil {
+		return nil
+	}
+
+	// Look for points inside the triangle of the hole point, segment intersection and endpoint.
+	// If there are no such points, we have a valid connection.
+	// Otherwise choose the point of the minimum angle with the ray as a connection point.
+	stop := m
+	mpos := m.pos
+	tanMin := 0.0
+	found := false
+	p = m
+	for {
+		if h.X >= p.pos.X && p.pos.X >= mpos.X && h.X != p.pos.X {
+			var a, c Vec
+			if h.Y < mpos.Y {
+				a = Vec{X: h.X, Y: h.Y}
+				c = Vec{X: qx, Y: h.Y}
+			} else {
+				a = Vec{X: qx, Y: h.Y}
+				c = Vec{X: h.X, Y: h.Y}
+			}
+			if pointInTriangle(a, mpos, c, p.pos) {
+				tan := Abs(h.Y-p.pos.Y) / (h.X - p.pos.X)
+				if locallyInside(p, hole) {
+					better := !found || tan < tanMin ||
+						(tan == tanMin && (p.pos.X > m.pos.X || (p.pos.X == m.pos.X && sectorContainsSector(m, p))))
+					if better {
+						m = p
+						tanMin = tan
+						found = true
+					}
+				}
+			}
+		}
+		p = p.next
+		if p == stop {
+			break
+		}
+	}
+
+	return m
+}
+
+// sectorContainsSector reports whether sector in vertex m contains sector in vertex p
+// in the same coordinates.
+func sectorContainsSector(m, p *earNode) bool {
+	return earArea(m.prev, m, p.prev) > 0 && earArea(p.next, m, m.next) > 0
+}
+
+func leftmostEarNode(start *earNode) *earNode {
+	p := start
+	leftmost := start
+	for {
+		if p.pos.X < leftmost.pos.X || (p.pos.X == leftmost.pos.X && p.pos.Y < leftmost.pos.Y) {
+			leftmost = p
+		}
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	return leftmost
+}
+
+// isValidDiagonal reports whether a diagonal between a and b
+// lies inside the polygon and doesn't intersect its edges.
+func isValidDiagonal(a, b *earNode) bool {
+	if a.next.i == b.i || a.prev.i == b.i || earIntersectsPolygon(a, b) {
+		return false
+	}
+	locallyVisible := locallyInside(a, b) && locallyInside(b, a) && middleInside(a, b)
+	if locallyVisible && (earArea(a.prev, a, b.prev) != 0 || earArea(a, b.prev, b) != 0) {
+		// Does not create opposite-facing sectors.
+		return true
+	}
+	// A special zero-length case.
+	return a.pos == b.pos && earArea(a.prev, a, a.next) < 0 && earArea(b.prev, b, b.next) < 0
+}
+
+func earIntersectsPolygon(a, b *earNode) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i &&
+			earSegmentsIntersect(p, p.next, a, b) {
+			return true
+		}
+		p = p.next
+		if p == a {
+			break
+		}
+	}
+	return false
+}
+
+// locallyInside reports whether a diagonal a-b is locally inside the polygon.
+func locallyInside(a, b *earNode) bool {
+	if earArea(a.prev, a, a.next) > 0 {
+		return earArea(a, b, a.next) <= 0 && earArea(a, a.prev, b) <= 0
+	}
+	return earArea(a, b, a.prev) > 0 || earArea(a, a.next, b) > 0
+}
+
+// middleInside reports whether the middle point of a polygon diagonal is inside the polygon.
+func middleInside(a, b *earNode) bool {
+	p := a
+	inside := false
+	mid := a.pos.Midpoint(b.pos)
+	for {
+		if (p.pos.Y > mid.Y) != (p.next.pos.Y > mid.Y) && p.next.pos.Y != p.pos.Y &&
+			mid.X < (p.next.pos.X-p.pos.X)*(mid.Y-p.pos.Y)/(p.next.pos.Y-p.pos.Y)+p.pos.X {
+			inside = !inside
+		}
+		p = p.next
+		if p == a {
+			break
+		}
+	}
+	return inside
+}
+
+func earSegmentsIntersect(p1, q1, p2, q2 *earNode) bool {
+	o1 := exactSign(earArea(p1, q1, p2))
+	o2 := exactSign(earArea(p1, q1, q2))
+	o3 := exactSign(earArea(p2, q2, p1))
+	o4 := exactSign(earArea(p2, q2, q1))
+
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+
+	return (o1 == 0 && onSegment(p1.pos, q1.pos, p2.pos)) ||
+		(o2 == 0 && onSegment(p1.pos, q1.pos, q2.pos)) ||
+		(o3 == 0 && onSegment(p2.pos, q2.pos, p1.pos)) ||
+		(o4 == 0 && onSegment(p2.pos, q2.pos, q1.pos))
+}
+
+// earArea returns a doubled signed area of the p-q-r triangle.
+// A positive value means a convex turn for the clockwise polygons.
+func earArea(p, q, r *earNode) float64 {
+	return q.pos.Sub(p.pos).Cross(r.pos.Sub(q.pos))
+}
+
+func pointInTriangle(a, b, c, p Vec) bool {
+	return (c.X-p.X)*(a.Y-p.Y) >= (a.X-p.X)*(c.Y-p.Y) &&
+		(a.X-p.X)*(b.Y-p.Y) >= (b.X-p.X)*(a.Y-p.Y) &&
+		(b.X-p.X)*(c.Y-p.Y) >= (c.X-p.X)*(b.Y-p.Y)
+}
+
+func exactSign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,132 @@
+package gmath
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTriangulate(t *testing.T) {
+	tests := []struct {
+		name          string
+		outer         Polygon
+		holes         []Polygon
+		wantTriangles int
+	}{
+		{
+			name:          "empty",
+			outer:         nil,
+			wantTriangles: 0,
+		},
+		{
+			name:          "line",
+			outer:         Polygon{{0, 0}, {1, 1}},
+			wantTriangles: 0,
+		},
+		{
+			name:          "triangle",
+			outer:         Polygon{{0, 0}, {10, 0}, {0, 10}},
+			wantTriangles: 1,
+		},
+		{
+			name:          "square",
+			outer:         Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			wantTriangles: 2,
+		},
+		{
+			name:          "square_ccw",
+			outer:         Polygon{{0, 10}, {10, 10}, {10, 0}, {0, 0}},
+			wantTriangles: 2,
+		},
+		{
+			name:          "collinear",
+			outer:         Polygon{{0, 0}, {5, 0}, {10, 0}, {10, 5}, {10, 10}, {0, 10}},
+			wantTriangles: 4,
+		},
+		{
+			name:          "duplicates",
+			outer:         Polygon{{0, 0}, {0, 0}, {10, 0}, {10, 10}, {10, 10}, {0, 10}, {0, 0}},
+			wantTriangles: 2,
+		},
+		{
+			name:          "lshape",
+			outer:         Polygon{{0, 0}, {4, 0}, {4, 1}, {1, 1}, {1, 4}, {0, 4}},
+			wantTriangles: 4,
+		},
+		{
+			name:          "comb",
+			outer:         Polygon{{0, 0}, {1, 0}, {1, 5}, {2, 5}, {2, 0}, {3, 0}, {3, 5}, {4, 5}, {4, 0}, {5, 0}, {5, 6}, {0, 6}},
+			wantTriangles: 10,
+		},
+		{
+			name:          "square_with_hole",
+			outer:         Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			holes:         []Polygon{{{3, 3}, {7, 3}, {7, 7}, {3, 7}}},
+			wantTriangles: 8,
+		},
+		{
+			name:          "square_with_hole_same_winding",
+			outer:         Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			holes:         []Polygon{{{3, 7}, {7, 7}, {7, 3}, {3, 3}}},
+			wantTriangles: 8,
+		},
+		{
+			name:  "two_holes",
+			outer: Polygon{{0, 0}, {20, 0}, {20, 10}, {0, 10}},
+			holes: []Polygon{
+				{{12, 2}, {18, 2}, {18, 8}, {12, 8}},
+				{{2, 2}, {8, 2}, {8, 8}, {2, 8}},
+			},
+			wantTriangles: 12,
+		},
+		{
+			name:          "hole_triangle",
+			outer:         Polygon{{0, 0}, {100, 0}, {50, 100}},
+			holes:         []Polygon{{{40, 20}, {60, 20}, {50, 40}}},
+			wantTriangles: 6,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indices := Triangulate(test.outer, test.holes...)
+			if len(indices)%3 != 0 {
+				t.Fatalf("indices length is not a multiple of 3: %d", len(indices))
+			}
+			if len(indices)/3 != test.wantTriangles {
+				t.Fatalf("triangles count mismatch:\nhave: %d\nwant: %d", len(indices)/3, test.wantTriangles)
+			}
+
+			vertices := append(Polygon{}, test.outer...)
+			for _, h := range test.holes {
+				vertices = append(vertices, h...)
+			}
+			wantArea := test.outer.Area()
+			for _, h := range test.holes {
+				wantArea -= h.Area()
+			}
+			haveArea := 0.0
+			for i := 0; i < len(indices); i += 3 {
+				tri := Polygon{vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]}
+				if tri.Area() == 0 {
+					t.Fatalf("degenerate triangle %v", tri)
+				}
+				haveArea += tri.Area()
+				center := tri.Centroid()
+				for _, h := range test.holes {
+					if h.Contains(center) {
+						t.Fatalf("triangle %v is inside a hole", tri)
+					}
+				}
+			}
+			if math.Abs(haveArea-wantArea) > 1e-6 {
+				t.Fatalf("triangles area mismatch:\nhave: %v\nwant: %v", haveArea, wantArea)
+			}
+
+			indices2 := Triangulate(test.outer, test.holes...)
+			if !reflect.DeepEqual(indices, indices2) {
+				t.Fatalf("non-deterministic result:\n%v\n%v", indices, indices2)
+			}
+		})
+	}
+}
@@ -102,6 +102,16 @@ func (v vec[T]) Dot(v2 vec[T]) T {
 	return (v.X * v2.X) + (v.Y * v2.Y)
 }
 
+// Cross returns a 2D cross-product of the two vectors.
+// It's also known as a perpendicular dot product.
+//
+// The sign of the result tells on which side of v the v2 lies.
+// With the screen coordinates (Y axis pointing down), a positive value means
+// that v2 is rotated clockwise relative to v.
+func (v vec[T]) Cross(v2 vec[T]) T {
+	return (v.X * v2.Y) - (v.Y * v2.X)
+}
+
 // Len reports the length of this vector (also known as magnitude).
 func (v vec[T]) Len() T {
 	return T(math.Sqrt(float64(v.LenSquared())))
@@ -340,18 +350,21 @@ func (v vec[T]) BoundsRect(w, h T) Rect {
 }
 
 func (v vec[T]) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(make([]byte, 0, 16)), nil
+}
+
+func (v vec[T]) appendJSON(buf []byte) []byte {
 	if v.IsZero() {
 		// Zero vectors are quite common.
 		// Encode them with a shorter notation.
-		return []byte("[]"), nil
+		return append(buf, "[]"...)
 	}
-	buf := make([]byte, 0, 16)
 	buf = append(buf, '[')
 	buf = strconv.AppendFloat(buf, float64(v.X), 'f', -1, 64)
 	buf = append(buf, ',')
 	buf = strconv.AppendFloat(buf, float64(v.Y), 'f', -1, 64)
 	buf = append(buf, ']')
-	return buf, nil
+	return buf
 }
 
 func (v *vec[T]) UnmarshalJSON(data []byte) error {